@@ -0,0 +1,141 @@
+package graphs
+
+import (
+	"cmp"
+	"mayerus/csgo/collections"
+	"mayerus/csgo/internal/ordered"
+	"slices"
+)
+
+// Returns the bridges of an undirected graph: the edges whose removal
+// disconnects their endpoints. Each edge is given as its two vertex IDs,
+// smaller ID first, and the edges are sorted.
+func Bridges[T comparable](g collections.Grapher[T]) ([][2]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	bridges, _, _ := hopcroftTarjan(g)
+	return bridges, nil
+}
+
+// Returns the articulation points (cut vertices) of an undirected graph:
+// the vertices whose removal disconnects the graph, sorted by ID.
+func ArticulationPoints[T comparable](g collections.Grapher[T]) ([]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	_, articulationPoints, _ := hopcroftTarjan(g)
+	return articulationPoints, nil
+}
+
+// Returns the biconnected components of an undirected graph as sets of edges.
+// Every edge belongs to exactly one component. Edges are given as their two
+// vertex IDs, smaller ID first; each component is sorted, and the components
+// are ordered by their first edge. Self loops are ignored.
+func BiconnectedComponents[T comparable](g collections.Grapher[T]) ([][][2]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	_, _, components := hopcroftTarjan(g)
+	return components, nil
+}
+
+// Hopcroft-Tarjan depth-first search computing bridges,
+// articulation points and biconnected components in a single O(|V|+|E|) pass
+func hopcroftTarjan[T comparable](g collections.Grapher[T]) (bridges [][2]int, articulationPoints []int, components [][][2]int) {
+	discovery, low := map[int]int{}, map[int]int{}
+	parent := map[int]int{}
+	isArticulation := map[int]bool{}
+	edges := [][2]int{}
+	stack := &collections.Stack[*dfsFrame]{}
+
+	visit := func(id int) {
+		discovery[id] = len(discovery)
+		low[id] = discovery[id]
+		stack.Push(&dfsFrame{id, g.Successors(id), 0})
+	}
+
+	for _, root := range g.VertexIDs() {
+		if _, ok := discovery[root]; ok {
+			continue
+		}
+		visit(root)
+		rootChildren := 0
+
+		for !stack.Empty() {
+			frame, _ := stack.Peek()
+			id := frame.id
+			if frame.next < len(frame.successors) {
+				neighbour := frame.successors[frame.next]
+				frame.next++
+				if neighbour == id {
+					continue
+				}
+				if _, ok := discovery[neighbour]; !ok {
+					parent[neighbour] = id
+					if id == root {
+						rootChildren++
+					}
+					edges = append(edges, edgeKey(id, neighbour))
+					visit(neighbour)
+					continue
+				}
+				if p, ok := parent[id]; (!ok || neighbour != p) && discovery[neighbour] < discovery[id] {
+					// back edge to an ancestor
+					low[id] = min(low[id], discovery[neighbour])
+					edges = append(edges, edgeKey(id, neighbour))
+				}
+				continue
+			}
+
+			stack.Pop()
+			p, ok := parent[id]
+			if !ok {
+				continue
+			}
+			low[p] = min(low[p], low[id])
+			if low[id] > discovery[p] {
+				bridges = append(bridges, edgeKey(p, id))
+			}
+			if low[id] >= discovery[p] {
+				if p != root {
+					isArticulation[p] = true
+				}
+				// the edges above (p, id) on the edge stack form a component
+				tree := edgeKey(p, id)
+				i := len(edges) - 1
+				for edges[i] != tree {
+					i--
+				}
+				component := slices.Clone(edges[i:])
+				edges = edges[:i]
+				slices.SortFunc(component, compareEdges)
+				components = append(components, component)
+			}
+		}
+		if rootChildren > 1 {
+			isArticulation[root] = true
+		}
+	}
+
+	slices.SortFunc(bridges, compareEdges)
+	articulationPoints = ordered.Keys(isArticulation)
+	slices.SortFunc(components, func(a, b [][2]int) int {
+		return compareEdges(a[0], b[0])
+	})
+	return
+}
+
+func edgeKey(a, b int) [2]int {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+func compareEdges(a, b [2]int) int {
+	if c := cmp.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a[1], b[1])
+}
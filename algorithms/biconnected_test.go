@@ -0,0 +1,154 @@
+package graphs
+
+import (
+	"math/rand"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+func randomGraph(n int, p float64, seed int64) *collections.Graph[int] {
+	random := rand.New(rand.NewSource(seed))
+	g := &collections.Graph[int]{}
+	for i := 1; i <= n; i++ {
+		g.AddVertex(i)
+	}
+	for a := 1; a <= n; a++ {
+		for b := a + 1; b <= n; b++ {
+			if random.Float64() < p {
+				g.AddEdge(a, b)
+			}
+		}
+	}
+	return g
+}
+
+// Counts the connected components of g, ignoring the removed vertex and edge
+func countComponents(g collections.Grapher[int], removedVertex int, removedEdge [2]int) int {
+	visited := map[int]bool{removedVertex: true}
+	neighbours := func(id int) []int {
+		result := []int{}
+		for _, neighbour := range g.Successors(id) {
+			if edgeKey(id, neighbour) != removedEdge {
+				result = append(result, neighbour)
+			}
+		}
+		return result
+	}
+	count := 0
+	for _, id := range g.VertexIDs() {
+		if !visited[id] {
+			count++
+			depthFirst(id, neighbours, visited, func(int) {})
+		}
+	}
+	return count
+}
+
+func TestBridgesAndArticulationPoints(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		g := randomGraph(20, 0.12, seed)
+		components := countComponents(g, 0, [2]int{})
+
+		bridges, err := Bridges[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range g.VertexIDs() {
+			for _, b := range g.Successors(a) {
+				if a > b {
+					continue
+				}
+				isBridge := countComponents(g, 0, [2]int{a, b}) > components
+				if isBridge != slices.Contains(bridges, [2]int{a, b}) {
+					t.Fatalf("seed %v: edge %v-%v bridge mismatch, bridges: %v", seed, a, b, bridges)
+				}
+			}
+		}
+
+		points, err := ArticulationPoints[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range g.VertexIDs() {
+			// an isolated vertex disappears together with its own component
+			isolated := 0
+			if len(g.Successors(id)) == 0 {
+				isolated = 1
+			}
+			isPoint := countComponents(g, id, [2]int{})+isolated > components
+			if isPoint != slices.Contains(points, id) {
+				t.Fatalf("seed %v: vertex %v articulation mismatch, points: %v", seed, id, points)
+			}
+		}
+	}
+}
+
+func TestBiconnectedComponents(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		g := randomGraph(16, 0.15, seed)
+		components, err := BiconnectedComponents[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, _ := ArticulationPoints[int](g)
+
+		seen := map[[2]int]bool{}
+		componentsOf := map[int]int{}
+		for _, component := range components {
+			sub := &collections.Graph[int]{}
+			ids := map[int]int{}
+			for _, edge := range component {
+				if seen[edge] {
+					t.Fatalf("seed %v: edge %v in several components", seed, edge)
+				}
+				seen[edge] = true
+				for _, id := range edge {
+					if _, ok := ids[id]; !ok {
+						ids[id] = sub.AddVertex(id)
+						componentsOf[id]++
+					}
+				}
+				sub.AddEdge(ids[edge[0]], ids[edge[1]])
+			}
+			if countComponents(sub, 0, [2]int{}) != 1 {
+				t.Fatalf("seed %v: component %v is disconnected", seed, component)
+			}
+			if subPoints, _ := ArticulationPoints[int](sub); len(subPoints) > 0 {
+				t.Fatalf("seed %v: component %v has cut vertices %v", seed, component, subPoints)
+			}
+		}
+
+		for _, a := range g.VertexIDs() {
+			for _, b := range g.Successors(a) {
+				if !seen[edgeKey(a, b)] {
+					t.Fatalf("seed %v: edge %v-%v is in no component", seed, a, b)
+				}
+			}
+			if (componentsOf[a] > 1) != slices.Contains(points, a) {
+				t.Fatalf("seed %v: vertex %v shared by %v components, articulation points: %v", seed, a, componentsOf[a], points)
+			}
+		}
+	}
+}
+
+func TestBridgesWeighted(t *testing.T) {
+	g := &collections.WGraph[string]{}
+	a, b, c, d := g.AddVertex("a"), g.AddVertex("b"), g.AddVertex("c"), g.AddVertex("d")
+	g.AddEdge(a, b, 1)
+	g.AddEdge(b, c, 1)
+	g.AddEdge(c, a, 1)
+	g.AddEdge(c, d, 5)
+
+	bridges, _ := Bridges[string](g)
+	if !slices.Equal(bridges, [][2]int{{c, d}}) {
+		t.Fatalf("bridges = %v, expected %v", bridges, [][2]int{{c, d}})
+	}
+	points, _ := ArticulationPoints[string](g)
+	if !slices.Equal(points, []int{c}) {
+		t.Fatalf("articulation points = %v, expected %v", points, []int{c})
+	}
+	if _, err := Bridges[int](&collections.DiGraph[int]{}); err != ErrDirected {
+		t.Fatalf("expected ErrDirected, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package graphs
+
+import (
+	"errors"
+	"math"
+	"mayerus/csgo/collections"
+	"sync"
+)
+
+// The path based centralities run one shortest path search per source vertex.
+// With workers > 1 the sources are split between that many goroutines.
+
+var ErrNotConverged = errors.New("Did not converge")
+
+type PageRankOptions struct {
+	// Probability of following an edge rather than teleporting, 0.85 if nil.
+	// 0 makes the ranks the teleportation weights.
+	Damping *float64
+	// Convergence threshold on the L1 change of the ranks, 1e-10 if zero
+	Tolerance float64
+	// 100 if zero
+	MaxIterations int
+	// Teleportation weight of every vertex, uniform if nil.
+	// Vertices missing from the map get no teleportation weight.
+	Personalization map[int]float64
+}
+
+// PageRank of every vertex by power iteration. The ranks sum to 1.
+// Rank of vertices without successors is redistributed by the personalization.
+// Returns the last ranks together with ErrNotConverged if the tolerance
+// was not reached within MaxIterations.
+func PageRank[T comparable](g collections.Grapher[T], options PageRankOptions) (map[int]float64, error) {
+	damping, tolerance, iterations := 0.85, options.Tolerance, options.MaxIterations
+	if options.Damping != nil {
+		damping = *options.Damping
+	}
+	if tolerance == 0 {
+		tolerance = 1e-10
+	}
+	if iterations == 0 {
+		iterations = 100
+	}
+	if damping < 0 || damping > 1 || tolerance < 0 || iterations < 0 {
+		return nil, ErrInvalidParameter
+	}
+
+	ids := g.VertexIDs()
+	n := float64(len(ids))
+	teleport := map[int]float64{}
+	if options.Personalization == nil {
+		for _, id := range ids {
+			teleport[id] = 1 / n
+		}
+	} else {
+		total := 0.0
+		for id, weight := range options.Personalization {
+			if !g.HasVertex(id) {
+				return nil, &collections.UnknownVertexError{ID: id}
+			}
+			if weight < 0 {
+				return nil, ErrInvalidParameter
+			}
+			total += weight
+		}
+		if total == 0 {
+			return nil, ErrInvalidParameter
+		}
+		for id, weight := range options.Personalization {
+			teleport[id] = weight / total
+		}
+	}
+
+	successors := map[int][]int{}
+	rank := map[int]float64{}
+	for _, id := range ids {
+		successors[id] = g.Successors(id)
+		rank[id] = 1 / n
+	}
+
+	for i := 0; i < iterations; i++ {
+		dangling := 0.0
+		next := map[int]float64{}
+		for _, id := range ids {
+			if len(successors[id]) == 0 {
+				dangling += rank[id]
+				continue
+			}
+			share := rank[id] / float64(len(successors[id]))
+			for _, successor := range successors[id] {
+				next[successor] += share
+			}
+		}
+
+		change := 0.0
+		for _, id := range ids {
+			next[id] = damping*(next[id]+dangling*teleport[id]) + (1-damping)*teleport[id]
+			change += math.Abs(next[id] - rank[id])
+		}
+		rank = next
+		if change < tolerance {
+			return rank, nil
+		}
+	}
+	return rank, ErrNotConverged
+}
+
+// Degree of every vertex divided by the highest possible degree, |V|-1.
+// Both in-edges and out-edges count for directed graphs.
+func DegreeCentrality[T comparable](g collections.Grapher[T]) map[int]float64 {
+	result := map[int]float64{}
+	ids := g.VertexIDs()
+	if len(ids) < 2 {
+		for _, id := range ids {
+			result[id] = 0
+		}
+		return result
+	}
+	for _, id := range ids {
+		degree := len(g.Successors(id))
+		if g.Directed() {
+			degree += len(g.Predecessors(id))
+		}
+		result[id] = float64(degree) / float64(len(ids)-1)
+	}
+	return result
+}
+
+// Brandes' betweenness centrality: the number of shortest paths between
+// other vertex pairs passing through every vertex, O(|V|*|E|).
+// Paths of undirected graphs are counted once per pair.
+func Betweenness[T comparable](g collections.Grapher[T], workers int) map[int]float64 {
+	dense := denseOf(g)
+	result, _ := dense.perSource(workers, unweighted(dense), brandes)
+	return halveUndirected(g, result)
+}
+
+// Brandes' betweenness centrality with edge weights as lengths, O(|V|*|E|*log(|V|)).
+// Paths are counted once per pair.
+func WeightedBetweenness[T comparable](g *collections.WGraph[T], workers int) (map[int]float64, error) {
+	dense := denseOfWeighted(g)
+	result, err := dense.perSource(workers, dense.dijkstraPaths, brandes)
+	if err != nil {
+		return nil, err
+	}
+	return halveUndirected(g, result), nil
+}
+
+// Closeness centrality: the inverse average distance from every vertex to the
+// vertices it reaches, scaled by the fraction of the graph it reaches
+// (Wasserman and Faust), so that it is comparable across components.
+// Directed graphs use distances along out-edges.
+func Closeness[T comparable](g collections.Grapher[T], workers int) map[int]float64 {
+	dense := denseOf(g)
+	result, _ := dense.perSource(workers, unweighted(dense), closeness)
+	return result
+}
+
+// Closeness centrality with edge weights as lengths
+func WeightedCloseness[T comparable](g *collections.WGraph[T], workers int) (map[int]float64, error) {
+	dense := denseOfWeighted(g)
+	return dense.perSource(workers, dense.dijkstraPaths, closeness)
+}
+
+// Harmonic centrality: the sum of the inverse distances from every vertex
+// to all the others, unreachable vertices contribute 0.
+// Directed graphs use distances along out-edges.
+func Harmonic[T comparable](g collections.Grapher[T], workers int) map[int]float64 {
+	dense := denseOf(g)
+	result, _ := dense.perSource(workers, unweighted(dense), harmonic)
+	return result
+}
+
+// Harmonic centrality with edge weights as lengths
+func WeightedHarmonic[T comparable](g *collections.WGraph[T], workers int) (map[int]float64, error) {
+	dense := denseOfWeighted(g)
+	return dense.perSource(workers, dense.dijkstraPaths, harmonic)
+}
+
+func unweighted(g *denseGraph) func(int) (*shortestPaths, error) {
+	return func(source int) (*shortestPaths, error) {
+		return g.breadthFirstPaths(source), nil
+	}
+}
+
+// Adds the dependencies of the source on every vertex of its shortest path DAG
+func brandes(source int, paths *shortestPaths, scores []float64) {
+	dependency := make([]float64, len(scores))
+	for i := len(paths.order) - 1; i >= 0; i-- {
+		v := paths.order[i]
+		for _, u := range paths.predecessors[v] {
+			dependency[u] += paths.paths[u] / paths.paths[v] * (1 + dependency[v])
+		}
+		if v != source {
+			scores[v] += dependency[v]
+		}
+	}
+}
+
+func closeness(source int, paths *shortestPaths, scores []float64) {
+	total := 0.0
+	for _, v := range paths.order {
+		total += paths.distance[v]
+	}
+	reached := float64(len(paths.order) - 1)
+	if total == 0 {
+		return
+	}
+	scores[source] = reached / total * reached / float64(len(scores)-1)
+}
+
+func harmonic(source int, paths *shortestPaths, scores []float64) {
+	for _, v := range paths.order {
+		if v != source && paths.distance[v] > 0 {
+			scores[source] += 1 / paths.distance[v]
+		}
+	}
+}
+
+func halveUndirected[T comparable](g collections.Grapher[T], scores map[int]float64) map[int]float64 {
+	if !g.Directed() {
+		for id := range scores {
+			scores[id] /= 2
+		}
+	}
+	return scores
+}
+
+// Runs search from every vertex and lets score accumulate the results.
+// The sources are split into contiguous ranges between the workers, each with
+// its own scores, which are summed up in worker order once all are done.
+func (g *denseGraph) perSource(
+	workers int,
+	search func(source int) (*shortestPaths, error),
+	score func(source int, paths *shortestPaths, scores []float64),
+) (map[int]float64, error) {
+	n := len(g.ids)
+	workers = max(1, min(workers, n))
+	partial := make([][]float64, workers)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			scores := make([]float64, n)
+			for source := w * n / workers; source < (w+1)*n/workers; source++ {
+				paths, err := search(source)
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				score(source, paths, scores)
+			}
+			partial[w] = scores
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := map[int]float64{}
+	for i, id := range g.ids {
+		for w := range partial {
+			result[id] += partial[w][i]
+		}
+	}
+	return result, nil
+}
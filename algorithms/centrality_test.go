@@ -0,0 +1,141 @@
+package graphs
+
+import (
+	"math"
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func checkScores(name string, scores, expected map[int]float64, t *testing.T) {
+	for id, value := range expected {
+		if !closeTo(scores[id], value) {
+			t.Fatalf("%v of %v = %v, expected %v (all: %v)", name, id, scores[id], value, scores)
+		}
+	}
+}
+
+func TestBetweenness(t *testing.T) {
+	checkScores("path betweenness", Betweenness[int](Path(4), 1), map[int]float64{1: 0, 2: 2, 3: 2, 4: 0}, t)
+	checkScores("star betweenness", Betweenness[int](Star(5), 1), map[int]float64{1: 6, 2: 0}, t)
+	// two shortest paths between 1 and 3 share the load
+	checkScores("cycle betweenness", Betweenness[int](Cycle(4), 1), map[int]float64{1: 0.5, 2: 0.5}, t)
+
+	directed := diGraph(3, [][2]int{{1, 2}, {2, 3}})
+	checkScores("directed betweenness", Betweenness[int](directed, 1), map[int]float64{1: 0, 2: 1, 3: 0}, t)
+
+	// the heavy direct edge makes the detour through 3 shorter
+	g := &collections.WGraph[int]{}
+	for i := 0; i < 3; i++ {
+		g.AddVertex(i)
+	}
+	g.AddEdge(1, 2, 10)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(3, 2, 1)
+	weighted, err := WeightedBetweenness(g, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkScores("weighted betweenness", weighted, map[int]float64{1: 0, 2: 0, 3: 1}, t)
+}
+
+func TestParallelCentrality(t *testing.T) {
+	base, _ := BarabasiAlbert(150, 2, 5)
+	g := Weighted(base, IntegerWeights(1, 5), 5)
+
+	sequential := Betweenness[int](base, 1)
+	checkScores("parallel betweenness", Betweenness[int](base, 4), sequential, t)
+	checkScores("parallel closeness", Closeness[int](base, 3), Closeness[int](base, 1), t)
+	checkScores("parallel harmonic", Harmonic[int](base, 8), Harmonic[int](base, 1), t)
+
+	weighted, _ := WeightedBetweenness(g, 1)
+	parallel, _ := WeightedBetweenness(g, 4)
+	checkScores("parallel weighted betweenness", parallel, weighted, t)
+
+	// unit weights give the unweighted results
+	unit := Weighted(base, ConstantWeights(1), 0)
+	weighted, _ = WeightedBetweenness(unit, 2)
+	checkScores("unit weighted betweenness", weighted, sequential, t)
+	closeness, _ := WeightedCloseness(unit, 2)
+	checkScores("unit weighted closeness", closeness, Closeness[int](base, 1), t)
+}
+
+func TestClosenessAndHarmonic(t *testing.T) {
+	g := Path(3)
+	g.AddVertex(3)
+	// n = 4, vertex 2 reaches 2 others at total distance 2, 1 reaches 2 at distance 3
+	checkScores("closeness", Closeness[int](g, 1), map[int]float64{1: 2.0 / 3 * 2 / 3, 2: 1 * 2.0 / 3, 4: 0}, t)
+	checkScores("harmonic", Harmonic[int](g, 1), map[int]float64{1: 1.5, 2: 2, 4: 0}, t)
+	checkScores("degree", DegreeCentrality[int](g), map[int]float64{1: 1.0 / 3, 2: 2.0 / 3, 4: 0}, t)
+
+	negative := &collections.WGraph[int]{}
+	negative.AddVertex(0)
+	negative.AddVertex(0)
+	negative.AddEdge(1, 2, -1)
+	if _, err := WeightedHarmonic(negative, 1); err != ErrNegativeWeight {
+		t.Fatalf("expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func TestPageRank(t *testing.T) {
+	ranks, err := PageRank[int](Cycle(5), PageRankOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, rank := range ranks {
+		if !closeTo(rank, 0.2) {
+			t.Fatalf("cycle rank of %v = %v, expected 0.2", id, rank)
+		}
+	}
+
+	// 1 -> 2 with vertex 2 dangling
+	g := diGraph(2, [][2]int{{1, 2}})
+	ranks, err = PageRank[int](g, PageRankOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(ranks[1]+ranks[2], 1) || !(ranks[2] > ranks[1]) {
+		t.Fatalf("ranks %v do not sum to 1 or favour the link target", ranks)
+	}
+	// r1 = (1-d)/2 + d*r2/2, the dangling rank of 2 is shared uniformly
+	if !closeTo(ranks[1], 0.075+0.425*ranks[2]) {
+		t.Fatalf("rank %v does not satisfy the PageRank equation", ranks[1])
+	}
+
+	star := Star(6)
+	ranks, _ = PageRank[int](star, PageRankOptions{})
+	personalized, _ := PageRank[int](star, PageRankOptions{Personalization: map[int]float64{2: 1}})
+	if !(personalized[2] > ranks[2]) || !closeTo(personalized[3], personalized[4]) {
+		t.Fatalf("personalization has no effect: %v vs %v", personalized, ranks)
+	}
+	// without damping the ranks are the teleportation weights
+	undamped := 0.0
+	ranks, err = PageRank[int](star, PageRankOptions{Damping: &undamped, Personalization: map[int]float64{2: 3, 4: 1}})
+	if err != nil || !closeTo(ranks[2], 0.75) || !closeTo(ranks[4], 0.25) || ranks[1] != 0 {
+		t.Fatalf("undamped ranks %v: %v", ranks, err)
+	}
+	invalid := 1.5
+	if _, err := PageRank[int](star, PageRankOptions{Damping: &invalid}); err != ErrInvalidParameter {
+		t.Fatalf("damping %v accepted: %v", invalid, err)
+	}
+	if _, err := PageRank[int](star, PageRankOptions{MaxIterations: 1}); err != ErrNotConverged {
+		t.Fatalf("expected ErrNotConverged, got %v", err)
+	}
+	if _, err := PageRank[int](star, PageRankOptions{Personalization: map[int]float64{42: 1}}); err == nil {
+		t.Fatalf("personalization of an unknown vertex accepted")
+	}
+}
+
+func BenchmarkBetweenness(b *testing.B) {
+	g, _ := BarabasiAlbert(1000, 3, 1)
+	for _, workers := range []int{1, 4} {
+		b.Run(map[int]string{1: "sequential", 4: "4 workers"}[workers], func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Betweenness[int](g, workers)
+			}
+		})
+	}
+}
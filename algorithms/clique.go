@@ -0,0 +1,127 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"mayerus/csgo/internal/ordered"
+	"slices"
+)
+
+// Bron–Kerbosch enumeration of the maximal cliques of an undirected graph,
+// pivoting on the vertex with the most candidates as neighbours.
+// visit receives every maximal clique as sorted vertex IDs and may return
+// false to stop the enumeration. Self loops are ignored. A graph without
+// vertices has no cliques.
+func BronKerbosch[T comparable](g collections.Grapher[T], visit func(clique []int) bool) error {
+	if g.Directed() {
+		return ErrDirected
+	}
+	if g.VertexCount() == 0 {
+		return nil
+	}
+	neighbours := neighbourSets(g)
+	candidates := map[int]bool{}
+	for _, id := range g.VertexIDs() {
+		candidates[id] = true
+	}
+	bronKerbosch(neighbours, []int{}, candidates, map[int]bool{}, visit)
+	return nil
+}
+
+// Returns all the maximal cliques, sorted
+func MaximalCliques[T comparable](g collections.Grapher[T]) ([][]int, error) {
+	cliques := [][]int{}
+	err := BronKerbosch(g, func(clique []int) bool {
+		cliques = append(cliques, clique)
+		return true
+	})
+	slices.SortFunc(cliques, slices.Compare[[]int])
+	return cliques, err
+}
+
+// Returns a clique of maximum size, the lexicographically smallest among equals
+func MaximumClique[T comparable](g collections.Grapher[T]) ([]int, error) {
+	best := []int{}
+	err := BronKerbosch(g, func(clique []int) bool {
+		if len(clique) > len(best) || (len(clique) == len(best) && slices.Compare(clique, best) < 0) {
+			best = clique
+		}
+		return true
+	})
+	return best, err
+}
+
+// Returns false once visit asked to stop
+func bronKerbosch(neighbours map[int]map[int]bool, clique []int, candidates, excluded map[int]bool, visit func([]int) bool) bool {
+	if len(candidates) == 0 {
+		if len(excluded) > 0 {
+			return true
+		}
+		result := slices.Clone(clique)
+		slices.Sort(result)
+		return visit(result)
+	}
+
+	// the pivot's neighbours can wait for a clique containing the pivot
+	pivot, covered := 0, -1
+	for _, set := range []map[int]bool{candidates, excluded} {
+		for id := range set {
+			count := 0
+			for neighbour := range neighbours[id] {
+				if candidates[neighbour] {
+					count++
+				}
+			}
+			if count > covered || (count == covered && id < pivot) {
+				pivot, covered = id, count
+			}
+		}
+	}
+
+	for _, id := range ordered.Keys(candidates) {
+		if neighbours[pivot][id] {
+			continue
+		}
+		nextCandidates, nextExcluded := map[int]bool{}, map[int]bool{}
+		for neighbour := range neighbours[id] {
+			if candidates[neighbour] {
+				nextCandidates[neighbour] = true
+			}
+			if excluded[neighbour] {
+				nextExcluded[neighbour] = true
+			}
+		}
+		if !bronKerbosch(neighbours, append(clique, id), nextCandidates, nextExcluded, visit) {
+			return false
+		}
+		delete(candidates, id)
+		excluded[id] = true
+	}
+	return true
+}
+
+// Neighbour sets without self loops
+func neighbourSets[T comparable](g collections.Grapher[T]) map[int]map[int]bool {
+	neighbours := map[int]map[int]bool{}
+	for _, id := range g.VertexIDs() {
+		neighbours[id] = map[int]bool{}
+		for _, neighbour := range g.Successors(id) {
+			if neighbour != id {
+				neighbours[id][neighbour] = true
+			}
+		}
+	}
+	return neighbours
+}
+
+// Reports whether the vertices are pairwise adjacent
+func IsClique[T comparable](g collections.Grapher[T], ids []int) bool {
+	for i, a := range ids {
+		successors := g.Successors(a)
+		for _, b := range ids[i+1:] {
+			if _, found := slices.BinarySearch(successors, b); !found {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+// All maximal cliques by checking every vertex subset of a graph on vertices 1..n
+func bruteForceCliques(g *collections.Graph[int], n int) [][]int {
+	isClique := func(mask int) bool {
+		for a := 1; a <= n; a++ {
+			for b := a + 1; b <= n; b++ {
+				if mask&(1<<(a-1)) != 0 && mask&(1<<(b-1)) != 0 && !g.HasEdge(a, b) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	cliques := [][]int{}
+	for mask := 1; mask < 1<<n; mask++ {
+		if !isClique(mask) {
+			continue
+		}
+		maximal := true
+		for v := 0; v < n && maximal; v++ {
+			if mask&(1<<v) == 0 && isClique(mask|1<<v) {
+				maximal = false
+			}
+		}
+		if !maximal {
+			continue
+		}
+		clique := []int{}
+		for v := 0; v < n; v++ {
+			if mask&(1<<v) != 0 {
+				clique = append(clique, v+1)
+			}
+		}
+		cliques = append(cliques, clique)
+	}
+	slices.SortFunc(cliques, slices.Compare[[]int])
+	return cliques
+}
+
+func TestMaximalCliques(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g, _ := ErdosRenyi(12, 0.4, seed)
+		cliques, err := MaximalCliques[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := bruteForceCliques(g, 12)
+		if !slices.EqualFunc(cliques, expected, slices.Equal[[]int]) {
+			t.Fatalf("seed %v: cliques %v, expected %v", seed, cliques, expected)
+		}
+
+		maximum, _ := MaximumClique[int](g)
+		for _, clique := range expected {
+			if len(clique) > len(maximum) {
+				t.Fatalf("seed %v: maximum clique %v, found larger %v", seed, maximum, clique)
+			}
+		}
+		if !IsClique[int](g, maximum) {
+			t.Fatalf("seed %v: %v is not a clique", seed, maximum)
+		}
+	}
+
+	empty, _ := ErdosRenyi(0, 0.5, 0)
+	if cliques, err := MaximalCliques[int](empty); err != nil || len(cliques) != 0 {
+		t.Fatalf("cliques %v of an empty graph: %v", cliques, err)
+	}
+}
+
+func TestBronKerboschStops(t *testing.T) {
+	visited := 0
+	BronKerbosch[int](Cycle(10), func([]int) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Fatalf("enumeration went on after being stopped: %v cliques", visited)
+	}
+}
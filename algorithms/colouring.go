@@ -0,0 +1,161 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"mayerus/csgo/internal/ordered"
+	"slices"
+)
+
+// Colourings assign every vertex ID a colour 0..k-1 so that adjacent vertices
+// differ. Self loops are ignored.
+
+// Greedy colouring: every vertex in turn takes the smallest colour unused by
+// its neighbours. A nil order colours the vertices by decreasing degree
+// (Welsh–Powell), ties by ID.
+func GreedyColouring[T comparable](g collections.Grapher[T], order []int) (map[int]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	neighbours := neighbourSets(g)
+	if order == nil {
+		order = g.VertexIDs()
+		sortByDegree(order, neighbours)
+	}
+	colours := map[int]int{}
+	for _, id := range order {
+		if _, ok := neighbours[id]; !ok {
+			return nil, &collections.UnknownVertexError{ID: id}
+		}
+		colours[id] = smallestFreeColour(id, neighbours, colours)
+	}
+	return colours, nil
+}
+
+// DSatur colouring: repeatedly colours the vertex whose neighbours already use
+// the most distinct colours, ties by degree and then ID, with the smallest
+// colour available to it. Optimal for bipartite graphs, cycles and wheels.
+func DSaturColouring[T comparable](g collections.Grapher[T]) (map[int]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	neighbours := neighbourSets(g)
+	colours := map[int]int{}
+	for range neighbours {
+		id := mostSaturated(neighbours, colours)
+		colours[id] = smallestFreeColour(id, neighbours, colours)
+	}
+	return colours, nil
+}
+
+// Minimum colouring by backtracking, exponential in the number of vertices,
+// so meant for small graphs. Searches for k-colourings from the size of the
+// maximum clique up to the number of colours DSatur needs.
+func ExactColouring[T comparable](g collections.Grapher[T]) (map[int]int, error) {
+	best, err := DSaturColouring(g)
+	if err != nil {
+		return nil, err
+	}
+	clique, _ := MaximumClique(g)
+	neighbours := neighbourSets(g)
+	for k := len(clique); k < ColourCount(best); k++ {
+		colours := map[int]int{}
+		if colourWithin(k, 0, neighbours, colours) {
+			return colours, nil
+		}
+	}
+	return best, nil
+}
+
+// Reports whether every vertex of g is coloured and no edge joins equal colours
+func IsProperColouring[T comparable](g collections.Grapher[T], colours map[int]int) bool {
+	for _, id := range g.VertexIDs() {
+		colour, ok := colours[id]
+		if !ok {
+			return false
+		}
+		for _, neighbour := range g.Successors(id) {
+			if neighbour != id && colours[neighbour] == colour {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Number of distinct colours used
+func ColourCount(colours map[int]int) int {
+	distinct := map[int]bool{}
+	for _, colour := range colours {
+		distinct[colour] = true
+	}
+	return len(distinct)
+}
+
+// Tries to extend colours to a k-colouring, using colours 0..used-1 or the
+// next new one, so that permutations of the colours are not searched
+func colourWithin(k, used int, neighbours map[int]map[int]bool, colours map[int]int) bool {
+	if len(colours) == len(neighbours) {
+		return true
+	}
+	id := mostSaturated(neighbours, colours)
+	taken := map[int]bool{}
+	for neighbour := range neighbours[id] {
+		if colour, ok := colours[neighbour]; ok {
+			taken[colour] = true
+		}
+	}
+	for colour := 0; colour < min(k, used+1); colour++ {
+		if taken[colour] {
+			continue
+		}
+		colours[id] = colour
+		if colourWithin(k, max(used, colour+1), neighbours, colours) {
+			return true
+		}
+		delete(colours, id)
+	}
+	return false
+}
+
+// The uncoloured vertex with the most distinct neighbour colours,
+// ties by degree and then ID
+func mostSaturated(neighbours map[int]map[int]bool, colours map[int]int) int {
+	best, bestSaturation, bestDegree := 0, -1, -1
+	for _, id := range ordered.Keys(neighbours) {
+		if _, ok := colours[id]; ok {
+			continue
+		}
+		distinct := map[int]bool{}
+		for neighbour := range neighbours[id] {
+			if colour, ok := colours[neighbour]; ok {
+				distinct[colour] = true
+			}
+		}
+		saturation, degree := len(distinct), len(neighbours[id])
+		if saturation > bestSaturation || (saturation == bestSaturation && degree > bestDegree) {
+			best, bestSaturation, bestDegree = id, saturation, degree
+		}
+	}
+	return best
+}
+
+func smallestFreeColour(id int, neighbours map[int]map[int]bool, colours map[int]int) int {
+	taken := map[int]bool{}
+	for neighbour := range neighbours[id] {
+		if colour, ok := colours[neighbour]; ok {
+			taken[colour] = true
+		}
+	}
+	colour := 0
+	for taken[colour] {
+		colour++
+	}
+	return colour
+}
+
+// Stable sort of vertex IDs by decreasing degree
+func sortByDegree(ids []int, neighbours map[int]map[int]bool) {
+	slices.SortStableFunc(ids, func(a, b int) int {
+		return len(neighbours[b]) - len(neighbours[a])
+	})
+}
@@ -0,0 +1,88 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Chromatic number by trying every assignment of k colours to vertices 1..n
+func bruteForceChromatic(g *collections.Graph[int], n int) int {
+	for k := 1; ; k++ {
+		colours := map[int]int{}
+		total := 1
+		for i := 0; i < n; i++ {
+			total *= k
+		}
+		for code := 0; code < total; code++ {
+			for v, c := 1, code; v <= n; v, c = v+1, c/k {
+				colours[v] = c % k
+			}
+			if IsProperColouring[int](g, colours) {
+				return k
+			}
+		}
+	}
+}
+
+func petersen() *collections.Graph[int] {
+	g := Cycle(5)
+	for i := 1; i <= 5; i++ {
+		g.AddVertex(i + 4)
+		g.AddEdge(i, i+5)
+	}
+	for i := 0; i < 5; i++ {
+		g.AddEdge(6+i, 6+(i+2)%5)
+	}
+	return g
+}
+
+func TestColouring(t *testing.T) {
+	cases := []struct {
+		name      string
+		g         *collections.Graph[int]
+		chromatic int
+	}{
+		{"odd cycle", Cycle(7), 3},
+		{"even cycle", Cycle(8), 2},
+		{"complete", Complete(6), 6},
+		{"grid", Grid(4, 5), 2},
+		{"Petersen", petersen(), 3},
+	}
+	for _, c := range cases {
+		greedy, _ := GreedyColouring[int](c.g, nil)
+		dsatur, _ := DSaturColouring[int](c.g)
+		exact, err := ExactColouring[int](c.g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, colours := range map[string]map[int]int{"greedy": greedy, "DSatur": dsatur, "exact": exact} {
+			if !IsProperColouring[int](c.g, colours) {
+				t.Fatalf("%v: improper %v colouring %v", c.name, name, colours)
+			}
+		}
+		if ColourCount(dsatur) != c.chromatic || ColourCount(exact) != c.chromatic {
+			t.Fatalf("%v: DSatur uses %v colours, exact %v, expected %v", c.name, ColourCount(dsatur), ColourCount(exact), c.chromatic)
+		}
+	}
+}
+
+func TestExactColouring(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g, _ := ErdosRenyi(8, 0.5, seed)
+		exact, _ := ExactColouring[int](g)
+		if !IsProperColouring[int](g, exact) {
+			t.Fatalf("seed %v: improper colouring %v", seed, exact)
+		}
+		if expected := bruteForceChromatic(g, 8); ColourCount(exact) != expected {
+			t.Fatalf("seed %v: %v colours, chromatic number %v", seed, ColourCount(exact), expected)
+		}
+	}
+
+	g := Path(3)
+	if _, err := GreedyColouring[int](g, []int{1, 42}); err == nil {
+		t.Fatalf("unknown vertex in the order accepted")
+	}
+	if IsProperColouring[int](g, map[int]int{1: 0, 2: 1}) {
+		t.Fatalf("partial colouring reported proper")
+	}
+}
@@ -0,0 +1,284 @@
+package graphs
+
+import (
+	"math/rand"
+	"mayerus/csgo/collections"
+	"mayerus/csgo/internal/ordered"
+)
+
+// Community detection returns the community of every vertex ID, numbered from 0
+// in order of each community's smallest vertex ID, together with the modularity
+// of the partition. Graph edges weigh 1. Randomness is seeded, so results are
+// reproducible for a given seed.
+
+// Modularity of a partition of an undirected graph into communities:
+// the fraction of edge weight inside communities minus its expected value
+// if the edges were placed at random, preserving vertex strengths.
+func Modularity[T comparable](g collections.WeightedGrapher[T], communities map[int]int) (float64, error) {
+	if g.Directed() {
+		return 0, ErrDirected
+	}
+	dense := denseOfWeighted(g)
+	membership := make([]int, len(dense.ids))
+	for i, id := range dense.ids {
+		c, ok := communities[id]
+		if !ok {
+			return 0, &collections.UnknownVertexError{ID: id}
+		}
+		membership[i] = c
+	}
+	return newCommunityGraph(dense).modularity(membership), nil
+}
+
+// Louvain method: greedily moves vertices to the neighbouring community with
+// the highest modularity gain, then contracts the communities into single
+// vertices and repeats, until no move improves the modularity.
+func Louvain[T comparable](g collections.WeightedGrapher[T], seed int64) (map[int]int, float64, error) {
+	if g.Directed() {
+		return nil, 0, ErrDirected
+	}
+	random := rand.New(rand.NewSource(seed))
+	dense := denseOfWeighted(g)
+	original := newCommunityGraph(dense)
+
+	// community of every original vertex, refined level by level
+	membership := make([]int, len(dense.ids))
+	for i := range membership {
+		membership[i] = i
+	}
+	level := original
+	for {
+		communities, moved := level.localMoves(random)
+		if !moved {
+			break
+		}
+		communities = renumber(communities)
+		for i := range membership {
+			membership[i] = communities[membership[i]]
+		}
+		level = level.aggregate(communities)
+	}
+
+	membership = renumber(membership)
+	return dense.communityMap(membership), original.modularity(membership), nil
+}
+
+// Asynchronous label propagation: in random order, every vertex adopts the
+// label carrying the most edge weight among its neighbours, ties broken at
+// random, until every vertex holds such a label or maxIterations rounds pass
+// (100 if not positive).
+func LabelPropagation[T comparable](g collections.WeightedGrapher[T], seed int64, maxIterations int) (map[int]int, float64, error) {
+	if g.Directed() {
+		return nil, 0, ErrDirected
+	}
+	if maxIterations <= 0 {
+		maxIterations = 100
+	}
+	random := rand.New(rand.NewSource(seed))
+	dense := denseOfWeighted(g)
+	n := len(dense.ids)
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+	}
+
+	weight := make([]float64, n)
+	candidates := []int{}
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		stable := true
+		for _, u := range random.Perm(n) {
+			if len(dense.adjacency[u]) == 0 {
+				continue
+			}
+			touched := []int{}
+			for k, v := range dense.adjacency[u] {
+				if v == u {
+					continue
+				}
+				if weight[labels[v]] == 0 {
+					touched = append(touched, labels[v])
+				}
+				weight[labels[v]] += dense.weights[u][k]
+			}
+			best := 0.0
+			candidates = candidates[:0]
+			for _, label := range touched {
+				if weight[label] > best {
+					best = weight[label]
+					candidates = candidates[:0]
+				}
+				if weight[label] == best {
+					candidates = append(candidates, label)
+				}
+			}
+			current := labels[u]
+			currentIsBest := len(candidates) > 0 && weight[current] == best
+			for _, label := range touched {
+				weight[label] = 0
+			}
+			if len(candidates) == 0 || currentIsBest {
+				continue
+			}
+			labels[u] = candidates[random.Intn(len(candidates))]
+			stable = false
+		}
+		if stable {
+			break
+		}
+	}
+
+	labels = renumber(labels)
+	return dense.communityMap(labels), newCommunityGraph(dense).modularity(labels), nil
+}
+
+// Weighted undirected graph over vertices 0..n-1. A self loop of weight w
+// contributes w to its vertex's strength, the contracted communities keep
+// their inner weight as a self loop.
+type communityGraph struct {
+	neighbours [][]int
+	weights    [][]float64
+	// Total weight of the edges of every vertex
+	strength []float64
+	// Sum of all strengths, twice the total edge weight
+	total float64
+}
+
+func newCommunityGraph(dense *denseGraph) *communityGraph {
+	g := &communityGraph{neighbours: dense.adjacency, weights: dense.weights}
+	g.strength = make([]float64, len(dense.ids))
+	for u := range g.neighbours {
+		for _, w := range g.weights[u] {
+			g.strength[u] += w
+			g.total += w
+		}
+	}
+	return g
+}
+
+func (g *communityGraph) modularity(membership []int) float64 {
+	if g.total == 0 {
+		return 0
+	}
+	inner := map[int]float64{}
+	strength := map[int]float64{}
+	for u := range g.neighbours {
+		strength[membership[u]] += g.strength[u]
+		for k, v := range g.neighbours[u] {
+			if membership[u] == membership[v] {
+				inner[membership[u]] += g.weights[u][k]
+			}
+		}
+	}
+	q := 0.0
+	for c, s := range strength {
+		q += inner[c]/g.total - (s/g.total)*(s/g.total)
+	}
+	return q
+}
+
+// First Louvain phase: moves vertices between communities until no single
+// move increases the modularity. Reports whether any vertex moved.
+func (g *communityGraph) localMoves(random *rand.Rand) ([]int, bool) {
+	n := len(g.neighbours)
+	community := make([]int, n)
+	communityStrength := make([]float64, n)
+	for u := range community {
+		community[u] = u
+		communityStrength[u] = g.strength[u]
+	}
+	if g.total == 0 {
+		return community, false
+	}
+
+	// weight from the current vertex to every neighbouring community
+	linked := make([]float64, n)
+	moved := false
+	order := random.Perm(n)
+	for improved := true; improved; {
+		improved = false
+		for _, u := range order {
+			touched := []int{community[u]}
+			for k, v := range g.neighbours[u] {
+				if v == u {
+					continue
+				}
+				if linked[community[v]] == 0 {
+					touched = append(touched, community[v])
+				}
+				linked[community[v]] += g.weights[u][k]
+			}
+
+			old := community[u]
+			communityStrength[old] -= g.strength[u]
+			// gain of joining c, up to a common factor: linked[c] - strength(c)*k(u)/2m
+			best, bestGain := old, linked[old]-communityStrength[old]*g.strength[u]/g.total
+			for _, c := range touched[1:] {
+				if gain := linked[c] - communityStrength[c]*g.strength[u]/g.total; gain > bestGain+pathEpsilon {
+					best, bestGain = c, gain
+				}
+			}
+			communityStrength[best] += g.strength[u]
+			community[u] = best
+			for _, c := range touched {
+				linked[c] = 0
+			}
+			if best != old {
+				improved, moved = true, true
+			}
+		}
+	}
+	return community, moved
+}
+
+// Second Louvain phase: contracts every community (numbered 0..k-1) into a vertex
+func (g *communityGraph) aggregate(community []int) *communityGraph {
+	k := 0
+	for _, c := range community {
+		k = max(k, c+1)
+	}
+	weights := make([]map[int]float64, k)
+	for c := range weights {
+		weights[c] = map[int]float64{}
+	}
+	for u := range g.neighbours {
+		for i, v := range g.neighbours[u] {
+			weights[community[u]][community[v]] += g.weights[u][i]
+		}
+	}
+
+	aggregated := &communityGraph{
+		neighbours: make([][]int, k),
+		weights:    make([][]float64, k),
+		strength:   make([]float64, k),
+		total:      g.total,
+	}
+	for c := range weights {
+		for _, d := range ordered.Keys(weights[c]) {
+			aggregated.neighbours[c] = append(aggregated.neighbours[c], d)
+			aggregated.weights[c] = append(aggregated.weights[c], weights[c][d])
+			aggregated.strength[c] += weights[c][d]
+		}
+	}
+	return aggregated
+}
+
+// Renumbers labels 0..k-1 in order of first appearance
+func renumber(labels []int) []int {
+	numbers := map[int]int{}
+	result := make([]int, len(labels))
+	for i, label := range labels {
+		if _, ok := numbers[label]; !ok {
+			numbers[label] = len(numbers)
+		}
+		result[i] = numbers[label]
+	}
+	return result
+}
+
+func (g *denseGraph) communityMap(membership []int) map[int]int {
+	result := map[int]int{}
+	for i, id := range g.ids {
+		result[id] = membership[i]
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package graphs
+
+import (
+	"maps"
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Ring of cliques: k cliques of size s, consecutive cliques joined by one edge
+func ringOfCliques(k, s int) *collections.Graph[int] {
+	g := &collections.Graph[int]{}
+	for i := 0; i < k*s; i++ {
+		g.AddVertex(i)
+	}
+	for c := 0; c < k; c++ {
+		for a := 1; a <= s; a++ {
+			for b := a + 1; b <= s; b++ {
+				g.AddEdge(c*s+a, c*s+b)
+			}
+		}
+		g.AddEdge(c*s+1, ((c+1)%k)*s+2)
+	}
+	return g
+}
+
+func checkCliqueCommunities(name string, communities map[int]int, k, s int, t *testing.T) {
+	for c := 0; c < k; c++ {
+		for a := 1; a <= s; a++ {
+			if communities[c*s+a] != c {
+				t.Fatalf("%v: vertex %v in community %v, expected %v: %v", name, c*s+a, communities[c*s+a], c, communities)
+			}
+		}
+	}
+}
+
+func TestLouvain(t *testing.T) {
+	g := ringOfCliques(6, 5)
+	communities, modularity, err := Louvain[int](g, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCliqueCommunities("Louvain", communities, 6, 5, t)
+
+	// 6 cliques of 10 inner edges and strength 22 each, 66 edges in total
+	expected := 6 * (10.0/66 - (22.0/132)*(22.0/132))
+	if !closeTo(modularity, expected) {
+		t.Fatalf("modularity %v, expected %v", modularity, expected)
+	}
+	if q, _ := Modularity[int](g, communities); !closeTo(q, modularity) {
+		t.Fatalf("Modularity() = %v, Louvain reported %v", q, modularity)
+	}
+
+	again, _, _ := Louvain[int](g, 5)
+	if !maps.Equal(communities, again) {
+		t.Fatalf("Louvain is not reproducible for a seed")
+	}
+
+	weighted := Weighted(g, ConstantWeights(1), 0)
+	unit, unitModularity, _ := Louvain[int](weighted, 1)
+	if !maps.Equal(communities, unit) || !closeTo(modularity, unitModularity) {
+		t.Fatalf("unit weights give a different partition")
+	}
+}
+
+func TestLouvainWeights(t *testing.T) {
+	// a path whose heavy edges pair up the vertices
+	g := &collections.WGraph[int]{}
+	for i := 0; i < 6; i++ {
+		g.AddVertex(i)
+	}
+	for id := 1; id < 6; id++ {
+		weight := 1.0
+		if id%2 == 1 {
+			weight = 10
+		}
+		g.AddEdge(id, id+1, weight)
+	}
+	communities, _, _ := Louvain[int](g, 5)
+	for _, pair := range [][2]int{{1, 2}, {3, 4}, {5, 6}} {
+		if communities[pair[0]] != communities[pair[1]] {
+			t.Fatalf("heavy edge %v split: %v", pair, communities)
+		}
+	}
+	if communities[2] == communities[3] || communities[4] == communities[5] {
+		t.Fatalf("light edges not cut: %v", communities)
+	}
+}
+
+func TestLabelPropagation(t *testing.T) {
+	g := ringOfCliques(5, 6)
+	communities, modularity, err := LabelPropagation[int](g, 7, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCliqueCommunities("label propagation", communities, 5, 6, t)
+	if q, _ := Modularity[int](g, communities); !closeTo(q, modularity) {
+		t.Fatalf("Modularity() = %v, label propagation reported %v", q, modularity)
+	}
+
+	again, _, _ := LabelPropagation[int](g, 7, 0)
+	if !maps.Equal(communities, again) {
+		t.Fatalf("label propagation is not reproducible for a seed")
+	}
+	if _, _, err := LabelPropagation[int](&collections.DiGraph[int]{}, 1, 0); err != ErrDirected {
+		t.Fatalf("expected ErrDirected, got %v", err)
+	}
+}
+
+func TestModularity(t *testing.T) {
+	g := Complete(4)
+	single := map[int]int{1: 0, 2: 0, 3: 0, 4: 0}
+	if q, _ := Modularity[int](g, single); !closeTo(q, 0) {
+		t.Fatalf("single community modularity %v, expected 0", q)
+	}
+	if _, err := Modularity[int](g, map[int]int{1: 0}); err == nil {
+		t.Fatalf("partial partition accepted")
+	}
+}
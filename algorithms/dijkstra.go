@@ -0,0 +1,204 @@
+package graphs
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Path lengths closer than epsilon are considered equal
+const pathEpsilon = 1e-9
+
+var ErrNegativeWeight = errors.New("Edge weight is negative")
+
+// Priority of a vertex in a search: its tentative distance, ties broken by
+// the vertex index, which follows the IDs, so that vertices at equal distance
+// are visited in ascending ID order whatever the heap
+type SearchKey struct {
+	Distance float64
+	Index    int
+}
+
+func compareSearchKeys(a, b SearchKey) int {
+	return cmp.Or(cmp.Compare(a.Distance, b.Distance), cmp.Compare(a.Index, b.Index))
+}
+
+// Creates the priority queue of a search over vertex indices, ordered by compare.
+// A nil factory stands for the binary collections.IndexedPriorityQueue.
+type HeapFactory func(compare func(a, b SearchKey) int) collections.PriorityHeap[int, SearchKey]
+
+func (f HeapFactory) newHeap() collections.PriorityHeap[int, SearchKey] {
+	if f == nil {
+		return collections.NewIndexedPriorityQueue[int](compareSearchKeys)
+	}
+	return f(compareSearchKeys)
+}
+
+// Single source shortest paths with the priority queue made by newHeap, nil for
+// the default binary heap. Returns the distance of every vertex reachable from
+// the source and the predecessor of every reached vertex but the source on one
+// of its shortest paths. O((|E|+|V|)*log(|V|)) with a binary heap,
+// O(|E|+|V|*log(|V|)) with a Fibonacci heap.
+func Dijkstra[T comparable](g collections.WeightedGrapher[T], source int, newHeap HeapFactory) (map[int]float64, map[int]int, error) {
+	if !g.HasVertex(source) {
+		return nil, nil, &collections.UnknownVertexError{ID: source}
+	}
+	dense := denseOfWeighted(g)
+	paths, err := dense.dijkstra(dense.index[source], nil, newHeap)
+	if err != nil {
+		return nil, nil, err
+	}
+	distances, predecessors := map[int]float64{}, map[int]int{}
+	for _, v := range paths.order {
+		distances[dense.ids[v]] = paths.distance[v]
+		if len(paths.predecessors[v]) > 0 {
+			predecessors[dense.ids[v]] = dense.ids[paths.predecessors[v][0]]
+		}
+	}
+	return distances, predecessors, nil
+}
+
+// Snapshot of a graph with its vertices renumbered 0..n-1 in ascending ID order,
+// so that repeated searches work on slices rather than maps
+type denseGraph struct {
+	ids   []int
+	index map[int]int
+	// Successor indices of every vertex, in ascending ID order
+	adjacency [][]int
+	// Weight of every adjacency entry, nil for unweighted graphs
+	weights [][]float64
+}
+
+func denseOf[T comparable](g collections.Grapher[T]) *denseGraph {
+	dense := &denseGraph{ids: g.VertexIDs(), index: map[int]int{}}
+	for i, id := range dense.ids {
+		dense.index[id] = i
+	}
+	dense.adjacency = make([][]int, len(dense.ids))
+	for i, id := range dense.ids {
+		for _, successor := range g.Successors(id) {
+			dense.adjacency[i] = append(dense.adjacency[i], dense.index[successor])
+		}
+	}
+	return dense
+}
+
+func denseOfWeighted[T comparable](g collections.WeightedGrapher[T]) *denseGraph {
+	dense := denseOf[T](g)
+	dense.weights = make([][]float64, len(dense.ids))
+	for i, id := range dense.ids {
+		for _, j := range dense.adjacency[i] {
+			weight, _ := g.Weight(id, dense.ids[j])
+			dense.weights[i] = append(dense.weights[i], weight)
+		}
+	}
+	return dense
+}
+
+// Shortest paths from a single source over a dense graph, as computed by BFS or Dijkstra
+type shortestPaths struct {
+	// Reached vertices in order of non-decreasing distance
+	order []int
+	// Distance from the source, +Inf for unreached vertices
+	distance []float64
+	// Number of distinct shortest paths from the source
+	paths []float64
+	// Predecessors of every vertex on its shortest paths
+	predecessors [][]int
+}
+
+func newShortestPaths(n, source int) *shortestPaths {
+	result := &shortestPaths{
+		order:        make([]int, 0, n),
+		distance:     make([]float64, n),
+		paths:        make([]float64, n),
+		predecessors: make([][]int, n),
+	}
+	for i := range result.distance {
+		result.distance[i] = math.Inf(1)
+	}
+	result.distance[source] = 0
+	result.paths[source] = 1
+	return result
+}
+
+// Unweighted single source shortest paths (breadth-first search)
+func (g *denseGraph) breadthFirstPaths(source int) *shortestPaths {
+	result := newShortestPaths(len(g.ids), source)
+	result.order = append(result.order, source)
+	// the order doubles as the BFS queue
+	for head := 0; head < len(result.order); head++ {
+		u := result.order[head]
+		for _, v := range g.adjacency[u] {
+			if math.IsInf(result.distance[v], 1) {
+				result.distance[v] = result.distance[u] + 1
+				result.order = append(result.order, v)
+			}
+			if result.distance[v] == result.distance[u]+1 {
+				result.paths[v] += result.paths[u]
+				result.predecessors[v] = append(result.predecessors[v], u)
+			}
+		}
+	}
+	return result
+}
+
+// Weighted single source shortest paths (Dijkstra)
+func (g *denseGraph) dijkstraPaths(source int) (*shortestPaths, error) {
+	return g.dijkstra(source, nil, nil)
+}
+
+// Dijkstra ignoring the adjacency entries k of u for which skip(u, k) holds
+func (g *denseGraph) dijkstra(source int, skip func(u, k int) bool, newHeap HeapFactory) (*shortestPaths, error) {
+	result := newShortestPaths(len(g.ids), source)
+	done := make([]bool, len(g.ids))
+	handles := make([]*collections.PriorityHandle[int, SearchKey], len(g.ids))
+	queue := newHeap.newHeap()
+	queue.Push(source, SearchKey{0, source})
+	for !queue.Empty() {
+		u, key, _ := queue.Pop()
+		distance := key.Distance
+		done[u] = true
+		result.order = append(result.order, u)
+
+		for k, v := range g.adjacency[u] {
+			w := g.weights[u][k]
+			if w < 0 {
+				return nil, ErrNegativeWeight
+			}
+			if done[v] || skip != nil && skip(u, k) {
+				continue
+			}
+			candidate := distance + w
+			current := result.distance[v]
+			switch {
+			case candidate < current-pathEpsilon:
+				result.distance[v] = candidate
+				result.paths[v] = result.paths[u]
+				result.predecessors[v] = append(result.predecessors[v][:0], u)
+				if handles[v] == nil {
+					handles[v] = queue.Push(v, SearchKey{candidate, v})
+				} else {
+					queue.DecreaseKey(handles[v], SearchKey{candidate, v})
+				}
+			case candidate <= current+pathEpsilon:
+				result.paths[v] += result.paths[u]
+				result.predecessors[v] = append(result.predecessors[v], u)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Vertices of a shortest path from the source to v, following the first predecessors
+func (p *shortestPaths) pathTo(v int) []int {
+	path := []int{v}
+	for len(p.predecessors[v]) > 0 {
+		v = p.predecessors[v][0]
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
@@ -0,0 +1,102 @@
+package graphs
+
+import (
+	"math"
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Bellman-Ford distances from the source, +Inf for unreachable vertices
+func bellmanFord(g collections.WeightedGrapher[int], source int) map[int]float64 {
+	distances := map[int]float64{}
+	for _, id := range g.VertexIDs() {
+		distances[id] = math.Inf(1)
+	}
+	distances[source] = 0
+	for range g.VertexIDs() {
+		for _, id := range g.VertexIDs() {
+			for _, successor := range g.Successors(id) {
+				weight, _ := g.Weight(id, successor)
+				distances[successor] = min(distances[successor], distances[id]+weight)
+			}
+		}
+	}
+	return distances
+}
+
+func TestDijkstra(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		base, _ := ErdosRenyi(50, 0.06, seed)
+		g := Weighted(base, UniformWeights(0, 10), seed).ToWDiGraph()
+		// one way shortcuts
+		for i := 2; i < 50; i += 7 {
+			g.AddEdge(i, i+1, 0.5)
+		}
+		expected := bellmanFord(g, 1)
+		for _, factory := range heapFactories {
+			distances, predecessors, err := Dijkstra(g, 1, factory.newHeap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id, distance := range expected {
+				got, reached := distances[id]
+				if reached == math.IsInf(distance, 1) || reached && math.Abs(got-distance) > 1e-9 {
+					t.Fatalf("%v heap, seed %v: distance to %v is %v, expected %v", factory.name, seed, id, got, distance)
+				}
+				if predecessor, found := predecessors[id]; found {
+					weight, err := g.Weight(predecessor, id)
+					if err != nil || math.Abs(distances[predecessor]+weight-got) > 1e-9 {
+						t.Fatalf("%v heap: %v does not precede %v on a shortest path", factory.name, predecessor, id)
+					}
+				} else if reached && id != 1 {
+					t.Fatalf("%v heap: no predecessor of %v", factory.name, id)
+				}
+			}
+		}
+	}
+
+	g := Weighted(Path(3), ConstantWeights(-1), 0)
+	if _, _, err := Dijkstra(g, 1, nil); err != ErrNegativeWeight {
+		t.Fatalf("negative weights accepted: %v", err)
+	}
+	if _, _, err := Dijkstra(g, 7, nil); err == nil {
+		t.Fatalf("unknown source accepted")
+	}
+}
+
+// Vertices at equal distance are visited in ascending ID order, so the
+// predecessor of a vertex is the closest one on its shortest paths, the one
+// with the lowest ID among equals, whatever the heap
+func TestDijkstraTies(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		base, _ := ErdosRenyi(30, 0.2, seed)
+		g := Weighted(base, IntegerWeights(1, 2), seed)
+		expected := bellmanFord(g, 1)
+		for _, factory := range heapFactories {
+			_, predecessors, _ := Dijkstra(g, 1, factory.newHeap)
+			for _, v := range g.VertexIDs() {
+				best := -1
+				for _, u := range g.Predecessors(v) {
+					weight, _ := g.Weight(u, v)
+					if expected[u]+weight == expected[v] && (best == -1 || expected[u] < expected[best]) {
+						best = u
+					}
+				}
+				if predecessor, found := predecessors[v]; best != -1 && predecessor != best || best == -1 && found {
+					t.Fatalf("%v heap, seed %v: predecessor of %v is %v, expected %v", factory.name, seed, v, predecessor, best)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	g := Weighted(Grid(100, 100), UniformWeights(1, 2), 0)
+	for _, factory := range heapFactories {
+		b.Run(factory.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Dijkstra(g, 1, factory.newHeap)
+			}
+		})
+	}
+}
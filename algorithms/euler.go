@@ -0,0 +1,145 @@
+package graphs
+
+import (
+	"errors"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+var ErrNotEulerian = errors.New("Graph has no Eulerian path")
+
+// Hierholzer's algorithm: a closed walk using every edge exactly once, as vertex IDs
+// with the first vertex repeated at the end. Undirected graphs need every vertex
+// to have even degree, directed graphs equal in- and out-degrees, and the edges
+// must be (weakly) connected. Isolated vertices are ignored.
+// The walk starts at the smallest vertex with edges, empty for an edgeless graph.
+func EulerianCircuit[T comparable](g collections.Grapher[T]) ([]int, error) {
+	euler, start, open := newEulerGraph(g)
+	if open {
+		return nil, ErrNotEulerian
+	}
+	return euler.path(start)
+}
+
+// Hierholzer's algorithm: a walk using every edge exactly once, as vertex IDs.
+// A circuit is returned when one exists, otherwise the walk runs between the two
+// odd degree vertices (from the vertex with one more out-edge than in-edges
+// for directed graphs), starting at the smaller one.
+func EulerianPath[T comparable](g collections.Grapher[T]) ([]int, error) {
+	euler, start, _ := newEulerGraph(g)
+	return euler.path(start)
+}
+
+// Multigraph over vertex indices, edges are stored once and referred to by index
+type eulerGraph struct {
+	ids      []int
+	ends     [][2]int
+	incident [][]int
+	directed bool
+}
+
+// Returns the graph, the vertex index an Eulerian path must start from (-1 if there
+// is none) and whether that path cannot be closed
+func newEulerGraph[T comparable](g collections.Grapher[T]) (*eulerGraph, int, bool) {
+	dense := denseOf(g)
+	euler := &eulerGraph{ids: dense.ids, incident: make([][]int, len(dense.ids)), directed: g.Directed()}
+	// out-degree minus in-degree, or degree for undirected graphs
+	balance := make([]int, len(dense.ids))
+	for u, successors := range dense.adjacency {
+		for _, v := range successors {
+			if euler.directed {
+				euler.add(u, v)
+				balance[u]++
+				balance[v]--
+			} else if u <= v {
+				euler.add(u, v)
+				balance[u]++
+				balance[v]++
+			}
+		}
+	}
+
+	start, odd := -1, 0
+	for v, b := range balance {
+		if start == -1 && len(euler.incident[v]) > 0 {
+			start = v
+		}
+		if euler.directed && b != 0 {
+			odd++
+			if b != 1 && b != -1 {
+				return euler, -1, true
+			}
+		}
+		if !euler.directed && b%2 != 0 {
+			odd++
+		}
+	}
+	if odd == 0 {
+		return euler, start, false
+	}
+	if odd != 2 {
+		return euler, -1, true
+	}
+	for v, b := range balance {
+		if (euler.directed && b == 1) || (!euler.directed && b%2 != 0) {
+			return euler, v, true
+		}
+	}
+	return euler, -1, true
+}
+
+func (g *eulerGraph) add(u, v int) {
+	g.ends = append(g.ends, [2]int{u, v})
+	g.incident[u] = append(g.incident[u], len(g.ends)-1)
+	if !g.directed && u != v {
+		g.incident[v] = append(g.incident[v], len(g.ends)-1)
+	}
+}
+
+// Walks from start until every edge is used, as vertex IDs
+func (g *eulerGraph) path(start int) ([]int, error) {
+	if len(g.ends) == 0 {
+		return []int{}, nil
+	}
+	if start == -1 {
+		return nil, ErrNotEulerian
+	}
+	walk := g.walk(start)
+	if len(walk) != len(g.ends)+1 {
+		// some edges are not connected to the start
+		return nil, ErrNotEulerian
+	}
+	for i, v := range walk {
+		walk[i] = g.ids[v]
+	}
+	return walk, nil
+}
+
+// Iterative Hierholzer: extends the walk on the stack along unused edges and
+// emits vertices once they are exhausted, which yields the walk in reverse
+func (g *eulerGraph) walk(start int) []int {
+	used := make([]bool, len(g.ends))
+	next := make([]int, len(g.incident))
+	stack := collections.Stack[int]{start}
+	walk := make([]int, 0, len(g.ends)+1)
+	for !stack.Empty() {
+		v, _ := stack.Peek()
+		for next[v] < len(g.incident[v]) && used[g.incident[v][next[v]]] {
+			next[v]++
+		}
+		if next[v] == len(g.incident[v]) {
+			stack.Pop()
+			walk = append(walk, v)
+			continue
+		}
+		edge := g.incident[v][next[v]]
+		used[edge] = true
+		if g.directed {
+			stack.Push(g.ends[edge][1])
+		} else {
+			stack.Push(g.ends[edge][0] + g.ends[edge][1] - v)
+		}
+	}
+	slices.Reverse(walk)
+	return walk
+}
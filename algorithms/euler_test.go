@@ -0,0 +1,117 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Checks that the walk uses every edge of g exactly once
+func checkEulerian(name string, g collections.Grapher[int], walk []int, t *testing.T) {
+	edges := map[[2]int]int{}
+	total := 0
+	for _, id := range g.VertexIDs() {
+		for _, successor := range g.Successors(id) {
+			if g.Directed() {
+				edges[[2]int{id, successor}]++
+				total++
+			} else if id <= successor {
+				edges[edgeKey(id, successor)]++
+				total++
+			}
+		}
+	}
+	if len(walk) != total+1 {
+		t.Fatalf("%v: walk %v has %v edges, expected %v", name, walk, len(walk)-1, total)
+	}
+	for i := 1; i < len(walk); i++ {
+		key := [2]int{walk[i-1], walk[i]}
+		if !g.Directed() {
+			key = edgeKey(walk[i-1], walk[i])
+		}
+		if edges[key] == 0 {
+			t.Fatalf("%v: walk %v uses %v-%v twice or without an edge", name, walk, walk[i-1], walk[i])
+		}
+		edges[key]--
+	}
+}
+
+func TestEulerianCircuit(t *testing.T) {
+	loop := Cycle(4)
+	loop.AddEdge(2, 2)
+	graphs := map[string]collections.Grapher[int]{
+		"cycle":     Cycle(6),
+		"K5":        Complete(5),
+		"K7":        Complete(7),
+		"self loop": loop,
+		"bowtie":    diGraph(5, [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 3}}),
+	}
+	for name, g := range graphs {
+		walk, err := EulerianCircuit(g)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		checkEulerian(name, g, walk, t)
+		if walk[0] != walk[len(walk)-1] || walk[0] != 1 {
+			t.Fatalf("%v: circuit %v is not closed at vertex 1", name, walk)
+		}
+	}
+
+	twoTriangles := Cycle(3)
+	for i := 4; i <= 6; i++ {
+		twoTriangles.AddVertex(i - 1)
+	}
+	twoTriangles.AddEdge(4, 5)
+	twoTriangles.AddEdge(5, 6)
+	twoTriangles.AddEdge(6, 4)
+	failing := map[string]collections.Grapher[int]{
+		"path":          Path(4),
+		"K4":            Complete(4),
+		"disconnected":  twoTriangles,
+		"directed path": diGraph(3, [][2]int{{1, 2}, {2, 3}}),
+	}
+	for name, g := range failing {
+		if _, err := EulerianCircuit(g); err != ErrNotEulerian {
+			t.Fatalf("%v: expected ErrNotEulerian, got %v", name, err)
+		}
+	}
+
+	if walk, err := EulerianCircuit[int](Path(1)); err != nil || len(walk) != 0 {
+		t.Fatalf("edgeless graph: %v, %v", walk, err)
+	}
+}
+
+func TestEulerianPath(t *testing.T) {
+	house := Cycle(4)
+	house.AddVertex(4)
+	house.AddEdge(3, 5)
+	house.AddEdge(4, 5)
+	house.AddEdge(2, 4)
+	graphs := map[string]struct {
+		g          collections.Grapher[int]
+		start, end int
+	}{
+		"path":     {Path(5), 1, 5},
+		"house":    {house, 2, 3},
+		"cycle":    {Cycle(5), 1, 1},
+		"directed": {diGraph(4, [][2]int{{2, 1}, {1, 3}, {3, 2}, {2, 4}}), 2, 4},
+	}
+	for name, c := range graphs {
+		walk, err := EulerianPath(c.g)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		checkEulerian(name, c.g, walk, t)
+		if walk[0] != c.start || walk[len(walk)-1] != c.end {
+			t.Fatalf("%v: path %v should run from %v to %v", name, walk, c.start, c.end)
+		}
+	}
+
+	for name, g := range map[string]collections.Grapher[int]{
+		"star":     Star(4),
+		"directed": diGraph(4, [][2]int{{1, 2}, {1, 3}, {1, 4}}),
+	} {
+		if _, err := EulerianPath(g); err != ErrNotEulerian {
+			t.Fatalf("%v: expected ErrNotEulerian, got %v", name, err)
+		}
+	}
+}
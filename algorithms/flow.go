@@ -0,0 +1,297 @@
+package graphs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Residual capacities below epsilon are treated as saturated
+const flowEpsilon = 1e-9
+
+var ErrNegativeCapacity = errors.New("Capacity is negative")
+
+// Result of a maximum flow computation
+type Flow struct {
+	Value float64
+	// Flow through every arc carrying a positive flow, keyed by {from, to}
+	Edges map[[2]int]float64
+	// Minimum cut partition: Source holds the vertices reachable from the source
+	// in the residual network, Sink the remaining vertices. Both are sorted by ID.
+	Source, Sink []int
+}
+
+// Maximum s-t flow using Dinic's algorithm, O(|V|^2*|E|).
+// The arc weights of g are the capacities, g itself is not modified.
+// Use WGraph.ToWDiGraph to compute flows over an undirected network.
+func MaxFlow[T comparable](g *collections.WDiGraph[T], s, t int) (*Flow, error) {
+	network, err := newFlowNetwork(g, s, t)
+	if err != nil {
+		return nil, err
+	}
+	source, sink := network.index[s], network.index[t]
+	for network.levelGraph(source, sink) {
+		next := make([]int, len(network.arcs))
+		for {
+			pushed := network.blockingFlow(source, sink, math.Inf(1), next)
+			if pushed <= flowEpsilon {
+				break
+			}
+			network.value += pushed
+		}
+	}
+	return network.result(source), nil
+}
+
+// Maximum s-t flow using the Edmonds-Karp algorithm, O(|V|*|E|^2).
+// Slower than MaxFlow, kept as a reference implementation.
+func EdmondsKarp[T comparable](g *collections.WDiGraph[T], s, t int) (*Flow, error) {
+	network, err := newFlowNetwork(g, s, t)
+	if err != nil {
+		return nil, err
+	}
+	source, sink := network.index[s], network.index[t]
+	for {
+		// breadth-first search for the shortest augmenting path
+		via := make([]int, len(network.arcs))
+		for i := range via {
+			via[i] = -1
+		}
+		queue := &collections.Queue[int]{}
+		queue.Push(source)
+		for !queue.Empty() && via[sink] == -1 {
+			u, _ := queue.Pop()
+			for _, a := range network.arcs[u] {
+				arc := network.edges[a]
+				if arc.to != source && via[arc.to] == -1 && arc.residual() > flowEpsilon {
+					via[arc.to] = a
+					queue.Push(arc.to)
+				}
+			}
+		}
+		if via[sink] == -1 {
+			break
+		}
+
+		bottleneck := math.Inf(1)
+		for v := sink; v != source; v = network.edges[via[v]^1].to {
+			bottleneck = min(bottleneck, network.edges[via[v]].residual())
+		}
+		for v := sink; v != source; v = network.edges[via[v]^1].to {
+			network.push(via[v], bottleneck)
+		}
+		network.value += bottleneck
+	}
+	return network.result(source), nil
+}
+
+type flowArc struct {
+	to       int
+	capacity float64
+	flow     float64
+}
+
+func (a *flowArc) residual() float64 {
+	return a.capacity - a.flow
+}
+
+// Residual network with vertices indexed 0..n-1.
+// Every arc is stored next to its reverse arc, so arc a is reversed by a^1.
+type flowNetwork struct {
+	ids   []int
+	index map[int]int
+	edges []flowArc
+	// Indices into edges of the arcs leaving every vertex
+	arcs  [][]int
+	level []int
+	value float64
+}
+
+func newFlowNetwork[T comparable](g *collections.WDiGraph[T], s, t int) (*flowNetwork, error) {
+	if !g.HasVertex(s) {
+		return nil, &collections.UnknownVertexError{ID: s}
+	}
+	if !g.HasVertex(t) {
+		return nil, &collections.UnknownVertexError{ID: t}
+	}
+	if s == t {
+		return nil, errors.New("Source and sink are the same vertex")
+	}
+
+	network := &flowNetwork{ids: g.VertexIDs(), index: map[int]int{}}
+	for i, id := range network.ids {
+		network.index[id] = i
+	}
+	network.arcs = make([][]int, len(network.ids))
+	for _, from := range network.ids {
+		for _, to := range g.Successors(from) {
+			capacity := g.Vertices[from].Out[to].Weight
+			if capacity < 0 {
+				return nil, fmt.Errorf("%w: %v -> %v", ErrNegativeCapacity, from, to)
+			}
+			u, v := network.index[from], network.index[to]
+			network.arcs[u] = append(network.arcs[u], len(network.edges))
+			network.edges = append(network.edges, flowArc{v, capacity, 0})
+			network.arcs[v] = append(network.arcs[v], len(network.edges))
+			network.edges = append(network.edges, flowArc{u, 0, 0})
+		}
+	}
+	return network, nil
+}
+
+func (n *flowNetwork) push(a int, amount float64) {
+	n.edges[a].flow += amount
+	n.edges[a^1].flow -= amount
+}
+
+// Labels every vertex with its BFS distance from the source in the residual
+// network. Returns false once the sink is no longer reachable.
+func (n *flowNetwork) levelGraph(source, sink int) bool {
+	n.level = make([]int, len(n.arcs))
+	for i := range n.level {
+		n.level[i] = -1
+	}
+	n.level[source] = 0
+	queue := &collections.Queue[int]{}
+	queue.Push(source)
+	for !queue.Empty() {
+		u, _ := queue.Pop()
+		for _, a := range n.arcs[u] {
+			arc := n.edges[a]
+			if n.level[arc.to] == -1 && arc.residual() > flowEpsilon {
+				n.level[arc.to] = n.level[u] + 1
+				queue.Push(arc.to)
+			}
+		}
+	}
+	return n.level[sink] != -1
+}
+
+// Pushes flow from u towards the sink along level-increasing arcs.
+// next[u] remembers the first arc of u that may still carry more flow.
+func (n *flowNetwork) blockingFlow(u, sink int, limit float64, next []int) float64 {
+	if u == sink {
+		return limit
+	}
+	for ; next[u] < len(n.arcs[u]); next[u]++ {
+		a := n.arcs[u][next[u]]
+		arc := n.edges[a]
+		if n.level[arc.to] != n.level[u]+1 || arc.residual() <= flowEpsilon {
+			continue
+		}
+		if pushed := n.blockingFlow(arc.to, sink, min(limit, arc.residual()), next); pushed > flowEpsilon {
+			n.push(a, pushed)
+			return pushed
+		}
+	}
+	return 0
+}
+
+func (n *flowNetwork) result(source int) *Flow {
+	flow := &Flow{Value: n.value, Edges: map[[2]int]float64{}}
+	for u, arcs := range n.arcs {
+		for _, a := range arcs {
+			arc := n.edges[a]
+			if arc.capacity > 0 && arc.flow > flowEpsilon {
+				flow.Edges[[2]int{n.ids[u], n.ids[arc.to]}] = arc.flow
+			}
+		}
+	}
+
+	reachable := make([]bool, len(n.arcs))
+	reachable[source] = true
+	queue := &collections.Queue[int]{}
+	queue.Push(source)
+	for !queue.Empty() {
+		u, _ := queue.Pop()
+		for _, a := range n.arcs[u] {
+			arc := n.edges[a]
+			if !reachable[arc.to] && arc.residual() > flowEpsilon {
+				reachable[arc.to] = true
+				queue.Push(arc.to)
+			}
+		}
+	}
+	for i, id := range n.ids {
+		if reachable[i] {
+			flow.Source = append(flow.Source, id)
+			continue
+		}
+		flow.Sink = append(flow.Sink, id)
+	}
+	return flow
+}
+
+// Global minimum cut of an undirected weighted graph (Stoer-Wagner), O(|V|^3).
+// Returns the total weight of the cut and the vertex IDs of one side, sorted.
+func StoerWagner[T comparable](g *collections.WGraph[T]) (float64, []int, error) {
+	ids := g.VertexIDs()
+	n := len(ids)
+	if n < 2 {
+		return 0, nil, errors.New("Graph has less than 2 vertices")
+	}
+
+	weights := make([][]float64, n)
+	groups := make([][]int, n)
+	for i, id := range ids {
+		weights[i] = make([]float64, n)
+		groups[i] = []int{id}
+		for j, other := range ids {
+			if edge, ok := g.Vertices[id].Edges[other]; ok && i != j {
+				if edge.Weight < 0 {
+					return 0, nil, fmt.Errorf("%w: %v - %v", ErrNegativeCapacity, id, other)
+				}
+				weights[i][j] = edge.Weight
+			}
+		}
+	}
+
+	active := make([]int, n)
+	for i := range active {
+		active[i] = i
+	}
+	best := math.Inf(1)
+	var bestSide []int
+
+	for len(active) > 1 {
+		// maximum adjacency ordering of the active (merged) vertices
+		connectivity := make([]float64, n)
+		added := make([]bool, n)
+		previous := -1
+		for step := range active {
+			last := -1
+			for _, v := range active {
+				if !added[v] && (last == -1 || connectivity[v] > connectivity[last]) {
+					last = v
+				}
+			}
+			added[last] = true
+
+			if step < len(active)-1 {
+				for _, v := range active {
+					connectivity[v] += weights[last][v]
+				}
+				previous = last
+				continue
+			}
+
+			// cut of the phase separates the last vertex from the rest
+			if connectivity[last] < best {
+				best = connectivity[last]
+				bestSide = slices.Clone(groups[last])
+			}
+			groups[previous] = append(groups[previous], groups[last]...)
+			for _, v := range active {
+				weights[previous][v] += weights[last][v]
+				weights[v][previous] = weights[previous][v]
+			}
+			weights[previous][previous] = 0
+			active = slices.DeleteFunc(active, func(v int) bool { return v == last })
+		}
+	}
+
+	slices.Sort(bestSide)
+	return best, bestSide, nil
+}
@@ -0,0 +1,165 @@
+package graphs
+
+import (
+	"math"
+	"math/rand"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+func randomCapacityGraph(n int, p float64, seed int64) *collections.WDiGraph[int] {
+	random := rand.New(rand.NewSource(seed))
+	g := &collections.WDiGraph[int]{}
+	for i := 1; i <= n; i++ {
+		g.AddVertex(i)
+	}
+	for from := 1; from <= n; from++ {
+		for to := 1; to <= n; to++ {
+			if from != to && random.Float64() < p {
+				g.AddEdge(from, to, float64(random.Intn(20)))
+			}
+		}
+	}
+	return g
+}
+
+func checkFlow(g *collections.WDiGraph[int], s, t int, flow *Flow, test *testing.T) {
+	balance := map[int]float64{}
+	for arc, amount := range flow.Edges {
+		if capacity := g.Vertices[arc[0]].Out[arc[1]].Weight; amount > capacity+flowEpsilon {
+			test.Fatalf("arc %v carries %v over its capacity %v", arc, amount, capacity)
+		}
+		balance[arc[0]] -= amount
+		balance[arc[1]] += amount
+	}
+	for id, b := range balance {
+		if id != s && id != t && math.Abs(b) > flowEpsilon {
+			test.Fatalf("flow is not conserved at vertex %v: %v", id, b)
+		}
+	}
+	if math.Abs(balance[t]-flow.Value) > flowEpsilon {
+		test.Fatalf("sink receives %v, flow value is %v", balance[t], flow.Value)
+	}
+
+	// max-flow min-cut theorem
+	if !slices.Contains(flow.Source, s) || !slices.Contains(flow.Sink, t) {
+		test.Fatalf("cut %v | %v does not separate %v from %v", flow.Source, flow.Sink, s, t)
+	}
+	cut := 0.0
+	for _, from := range flow.Source {
+		for to, arc := range g.Vertices[from].Out {
+			if slices.Contains(flow.Sink, to) {
+				cut += arc.Weight
+			}
+		}
+	}
+	if math.Abs(cut-flow.Value) > flowEpsilon {
+		test.Fatalf("cut capacity %v differs from flow value %v", cut, flow.Value)
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	// CLRS figure 26.1
+	g := &collections.WDiGraph[string]{}
+	s, v1, v2, v3, v4, sink := g.AddVertex("s"), g.AddVertex("v1"), g.AddVertex("v2"), g.AddVertex("v3"), g.AddVertex("v4"), g.AddVertex("t")
+	g.AddEdge(s, v1, 16)
+	g.AddEdge(s, v2, 13)
+	g.AddEdge(v2, v1, 4)
+	g.AddEdge(v1, v3, 12)
+	g.AddEdge(v3, v2, 9)
+	g.AddEdge(v2, v4, 14)
+	g.AddEdge(v4, v3, 7)
+	g.AddEdge(v3, sink, 20)
+	g.AddEdge(v4, sink, 4)
+
+	for name, maxFlow := range map[string]func(*collections.WDiGraph[string], int, int) (*Flow, error){
+		"Dinic":        MaxFlow[string],
+		"Edmonds-Karp": EdmondsKarp[string],
+	} {
+		flow, err := maxFlow(g, s, sink)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flow.Value != 23 {
+			t.Fatalf("%v: max flow = %v, expected 23", name, flow.Value)
+		}
+		if expected := []int{s, v1, v2, v4}; !slices.Equal(flow.Source, expected) {
+			t.Fatalf("%v: source side = %v, expected %v", name, flow.Source, expected)
+		}
+	}
+
+	if _, err := MaxFlow(g, s, 42); err == nil {
+		t.Fatalf("MaxFlow accepted an unknown sink")
+	}
+	if _, err := MaxFlow(g, s, s); err == nil {
+		t.Fatalf("MaxFlow accepted source == sink")
+	}
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		g := randomCapacityGraph(15, 0.25, seed)
+		dinic, err := MaxFlow(g, 1, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFlow(g, 1, 15, dinic, t)
+
+		edmondsKarp, _ := EdmondsKarp(g, 1, 15)
+		checkFlow(g, 1, 15, edmondsKarp, t)
+		if math.Abs(dinic.Value-edmondsKarp.Value) > flowEpsilon {
+			t.Fatalf("seed %v: Dinic %v, Edmonds-Karp %v", seed, dinic.Value, edmondsKarp.Value)
+		}
+	}
+}
+
+func TestStoerWagner(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		random := rand.New(rand.NewSource(seed))
+		g := &collections.WGraph[int]{}
+		n := 9
+		for i := 1; i <= n; i++ {
+			g.AddVertex(i)
+		}
+		for a := 1; a <= n; a++ {
+			for b := a + 1; b <= n; b++ {
+				if random.Float64() < 0.4 {
+					g.AddEdge(a, b, float64(1+random.Intn(10)))
+				}
+			}
+		}
+
+		weight, side, err := StoerWagner(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cutWeight := func(inside func(int) bool) float64 {
+			total := 0.0
+			for a, vertex := range g.Vertices {
+				for b, edge := range vertex.Edges {
+					if inside(a) && !inside(b) {
+						total += edge.Weight
+					}
+				}
+			}
+			return total
+		}
+
+		if len(side) == 0 || len(side) == n {
+			t.Fatalf("seed %v: trivial cut side %v", seed, side)
+		}
+		if w := cutWeight(func(id int) bool { return slices.Contains(side, id) }); w != weight {
+			t.Fatalf("seed %v: side %v has cut weight %v, reported %v", seed, side, w, weight)
+		}
+
+		// brute force over every bipartition
+		best := math.Inf(1)
+		for mask := 1; mask < 1<<n-1; mask++ {
+			best = min(best, cutWeight(func(id int) bool { return mask&(1<<(id-1)) != 0 }))
+		}
+		if best != weight {
+			t.Fatalf("seed %v: Stoer-Wagner cut %v, brute force %v", seed, weight, best)
+		}
+	}
+}
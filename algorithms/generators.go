@@ -0,0 +1,271 @@
+package graphs
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"mayerus/csgo/collections"
+)
+
+// Generated graphs hold vertices 1..n in creation order, the value of every
+// vertex is its 0-based position (row*columns+column for grids).
+// Random generators are deterministic for a given seed.
+
+var ErrInvalidParameter = errors.New("Invalid generator parameter")
+
+// Draws an edge weight from the given random source
+type WeightDistribution func(random *rand.Rand) float64
+
+func ConstantWeights(weight float64) WeightDistribution {
+	return func(*rand.Rand) float64 {
+		return weight
+	}
+}
+
+// Weights drawn uniformly from [low, high)
+func UniformWeights(low, high float64) WeightDistribution {
+	return func(random *rand.Rand) float64 {
+		return low + random.Float64()*(high-low)
+	}
+}
+
+// Whole weights drawn uniformly from [low, high]
+func IntegerWeights(low, high int) WeightDistribution {
+	return func(random *rand.Rand) float64 {
+		return float64(low + random.Intn(high-low+1))
+	}
+}
+
+func NormalWeights(mean, deviation float64) WeightDistribution {
+	return func(random *rand.Rand) float64 {
+		return mean + random.NormFloat64()*deviation
+	}
+}
+
+func ExponentialWeights(rate float64) WeightDistribution {
+	return func(random *rand.Rand) float64 {
+		return random.ExpFloat64() / rate
+	}
+}
+
+// Returns a weighted copy of g, drawing the weight of every edge (in Edges order)
+// from the distribution
+func Weighted(g *collections.Graph[int], weights WeightDistribution, seed int64) *collections.WGraph[int] {
+	random := rand.New(rand.NewSource(seed))
+	weighted := &collections.WGraph[int]{}
+	ids := map[int]int{}
+	for _, id := range g.VertexIDs() {
+		ids[id] = weighted.AddVertex(g.Vertices[id].Value)
+	}
+	for _, edge := range g.Edges() {
+		weighted.AddEdge(ids[edge[0]], ids[edge[1]], weights(random))
+	}
+	return weighted
+}
+
+func withVertices(n int) *collections.Graph[int] {
+	g := &collections.Graph[int]{}
+	for i := 0; i < n; i++ {
+		g.AddVertex(i)
+	}
+	return g
+}
+
+// Complete graph K(n)
+func Complete(n int) *collections.Graph[int] {
+	g := withVertices(n)
+	for a := 1; a <= n; a++ {
+		for b := a + 1; b <= n; b++ {
+			g.AddEdge(a, b)
+		}
+	}
+	return g
+}
+
+// Star with n vertices, vertex 1 is the centre
+func Star(n int) *collections.Graph[int] {
+	g := withVertices(n)
+	for leaf := 2; leaf <= n; leaf++ {
+		g.AddEdge(1, leaf)
+	}
+	return g
+}
+
+// Path 1 - 2 - ... - n
+func Path(n int) *collections.Graph[int] {
+	g := withVertices(n)
+	for id := 2; id <= n; id++ {
+		g.AddEdge(id-1, id)
+	}
+	return g
+}
+
+// Cycle 1 - 2 - ... - n - 1, n must be at least 3 for a simple cycle
+func Cycle(n int) *collections.Graph[int] {
+	g := Path(n)
+	if n >= 3 {
+		g.AddEdge(n, 1)
+	}
+	return g
+}
+
+// rows x columns lattice, the vertex at (row, column) has ID row*columns+column+1
+func Grid(rows, columns int) *collections.Graph[int] {
+	g := withVertices(rows * columns)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			id := row*columns + column + 1
+			if column+1 < columns {
+				g.AddEdge(id, id+1)
+			}
+			if row+1 < rows {
+				g.AddEdge(id, id+columns)
+			}
+		}
+	}
+	return g
+}
+
+// Erdős–Rényi G(n, p) random graph: every edge exists independently with probability p.
+// Runs in O(n+|E|) by skipping over the missing edges (Batagelj-Brandes).
+func ErdosRenyi(n int, p float64, seed int64) (*collections.Graph[int], error) {
+	if n < 0 || p < 0 || p > 1 {
+		return nil, ErrInvalidParameter
+	}
+	g := withVertices(n)
+	if p == 0 {
+		return g, nil
+	}
+	if p == 1 {
+		return Complete(n), nil
+	}
+
+	random := rand.New(rand.NewSource(seed))
+	logQ := math.Log(1 - p)
+	// walk the pairs (v, w) with w < v in order, jumping geometric gaps
+	v, w := 1, -1
+	for v < n {
+		w += 1 + int(math.Log(1-random.Float64())/logQ)
+		for w >= v && v < n {
+			w -= v
+			v++
+		}
+		if v < n {
+			g.AddEdge(v+1, w+1)
+		}
+	}
+	return g, nil
+}
+
+// Barabási–Albert preferential attachment graph: starting from a star of m+1
+// vertices, every new vertex attaches to m distinct vertices chosen with
+// probability proportional to their degree.
+func BarabasiAlbert(n, m int, seed int64) (*collections.Graph[int], error) {
+	if m < 1 || n <= m {
+		return nil, ErrInvalidParameter
+	}
+	random := rand.New(rand.NewSource(seed))
+	g := Star(m + 1)
+	// every vertex appears once per incident edge
+	ends := make([]int, 0, 2*m*n)
+	for _, edge := range g.Edges() {
+		ends = append(ends, edge[0], edge[1])
+	}
+
+	for id := m + 2; id <= n; id++ {
+		g.AddVertex(id - 1)
+		targets := map[int]bool{}
+		order := make([]int, 0, m)
+		for len(order) < m {
+			target := ends[random.Intn(len(ends))]
+			if !targets[target] {
+				targets[target] = true
+				order = append(order, target)
+			}
+		}
+		for _, target := range order {
+			g.AddEdge(id, target)
+			ends = append(ends, id, target)
+		}
+	}
+	return g, nil
+}
+
+// Watts–Strogatz small world graph: a ring where every vertex is joined to its
+// k nearest neighbours (k even), after which every edge is rewired to a random
+// vertex with probability beta, avoiding self loops and duplicate edges.
+func WattsStrogatz(n, k int, beta float64, seed int64) (*collections.Graph[int], error) {
+	if k < 0 || k%2 != 0 || k >= n || beta < 0 || beta > 1 {
+		return nil, ErrInvalidParameter
+	}
+	random := rand.New(rand.NewSource(seed))
+	g := withVertices(n)
+	for offset := 1; offset <= k/2; offset++ {
+		for id := 1; id <= n; id++ {
+			g.AddEdge(id, (id+offset-1)%n+1)
+		}
+	}
+
+	for offset := 1; offset <= k/2; offset++ {
+		for id := 1; id <= n; id++ {
+			neighbour := (id+offset-1)%n + 1
+			if random.Float64() >= beta || !g.HasEdge(id, neighbour) {
+				continue
+			}
+			if degree, _ := g.Degree(id); degree >= n-1 {
+				continue
+			}
+			target := 1 + random.Intn(n)
+			for target == id || g.HasEdge(id, target) {
+				target = 1 + random.Intn(n)
+			}
+			g.RemoveEdge(id, neighbour)
+			g.AddEdge(id, target)
+		}
+	}
+	return g, nil
+}
+
+// Uniformly random labelled tree on n vertices, decoded from a random Prüfer sequence
+func RandomTree(n int, seed int64) (*collections.Graph[int], error) {
+	if n < 0 {
+		return nil, ErrInvalidParameter
+	}
+	g := withVertices(n)
+	if n < 2 {
+		return g, nil
+	}
+	random := rand.New(rand.NewSource(seed))
+	sequence := make([]int, n-2)
+	degree := make([]int, n+1)
+	for id := 1; id <= n; id++ {
+		degree[id] = 1
+	}
+	for i := range sequence {
+		sequence[i] = 1 + random.Intn(n)
+		degree[sequence[i]]++
+	}
+
+	// linear time decoding: leaf tracks the smallest current leaf
+	pointer := 1
+	for degree[pointer] != 1 {
+		pointer++
+	}
+	leaf := pointer
+	for _, parent := range sequence {
+		g.AddEdge(leaf, parent)
+		degree[leaf]--
+		degree[parent]--
+		if degree[parent] == 1 && parent < pointer {
+			leaf = parent
+			continue
+		}
+		pointer++
+		for degree[pointer] != 1 {
+			pointer++
+		}
+		leaf = pointer
+	}
+	g.AddEdge(leaf, n)
+	return g, nil
+}
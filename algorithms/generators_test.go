@@ -0,0 +1,110 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+func connected(g *collections.Graph[int]) bool {
+	return countComponents(g, 0, [2]int{}) <= 1
+}
+
+func TestStructuredGenerators(t *testing.T) {
+	cases := []struct {
+		name            string
+		g               *collections.Graph[int]
+		vertices, edges int
+	}{
+		{"Complete", Complete(6), 6, 15},
+		{"Star", Star(6), 6, 5},
+		{"Path", Path(6), 6, 5},
+		{"Cycle", Cycle(6), 6, 6},
+		{"Grid", Grid(3, 4), 12, 17},
+	}
+	for _, c := range cases {
+		if c.g.VertexCount() != c.vertices || c.g.EdgeCount() != c.edges || !connected(c.g) {
+			t.Fatalf("%v: %v vertices and %v edges, expected %v and %v, connected",
+				c.name, c.g.VertexCount(), c.g.EdgeCount(), c.vertices, c.edges)
+		}
+	}
+	if degree, _ := Star(6).Degree(1); degree != 5 {
+		t.Fatalf("star centre degree %v, expected 5", degree)
+	}
+	if neighbours, _ := Grid(3, 4).Neighbors(6); !slices.Equal(neighbours, []int{2, 5, 7, 10}) {
+		t.Fatalf("grid neighbours of 6: %v, expected [2 5 7 10]", neighbours)
+	}
+}
+
+func TestRandomGenerators(t *testing.T) {
+	generators := map[string]func(seed int64) (*collections.Graph[int], error){
+		"ErdosRenyi":     func(seed int64) (*collections.Graph[int], error) { return ErdosRenyi(200, 0.05, seed) },
+		"BarabasiAlbert": func(seed int64) (*collections.Graph[int], error) { return BarabasiAlbert(200, 3, seed) },
+		"WattsStrogatz":  func(seed int64) (*collections.Graph[int], error) { return WattsStrogatz(200, 6, 0.2, seed) },
+		"RandomTree":     func(seed int64) (*collections.Graph[int], error) { return RandomTree(200, seed) },
+	}
+	for name, generate := range generators {
+		a, err := generate(7)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		b, _ := generate(7)
+		c, _ := generate(8)
+		if !slices.Equal(a.Edges(), b.Edges()) {
+			t.Fatalf("%v is not deterministic for a seed", name)
+		}
+		if slices.Equal(a.Edges(), c.Edges()) {
+			t.Fatalf("%v ignores the seed", name)
+		}
+		if a.VertexCount() != 200 {
+			t.Fatalf("%v: %v vertices, expected 200", name, a.VertexCount())
+		}
+		for _, edge := range a.Edges() {
+			if edge[0] == edge[1] {
+				t.Fatalf("%v: self loop on %v", name, edge[0])
+			}
+		}
+	}
+
+	tree, _ := RandomTree(200, 1)
+	if tree.EdgeCount() != 199 || !connected(tree) {
+		t.Fatalf("random tree has %v edges, connected: %v", tree.EdgeCount(), connected(tree))
+	}
+	ba, _ := BarabasiAlbert(200, 3, 1)
+	if ba.EdgeCount() != 3+3*196 || !connected(ba) {
+		t.Fatalf("Barabási–Albert graph has %v edges, expected %v", ba.EdgeCount(), 3+3*196)
+	}
+	ws, _ := WattsStrogatz(200, 6, 0.2, 1)
+	if ws.EdgeCount() != 600 {
+		t.Fatalf("Watts–Strogatz graph has %v edges, expected 600", ws.EdgeCount())
+	}
+	er, _ := ErdosRenyi(200, 0.05, 1)
+	if count := er.EdgeCount(); count < 800 || count > 1200 {
+		t.Fatalf("Erdős–Rényi graph has %v edges, expected about 995", count)
+	}
+	if _, err := ErdosRenyi(10, 1.5, 1); err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+}
+
+func TestWeighted(t *testing.T) {
+	g := Grid(5, 5)
+	a := Weighted(g, IntegerWeights(1, 9), 3)
+	b := Weighted(g, IntegerWeights(1, 9), 3)
+	if !slices.Equal(a.Edges(), g.Edges()) {
+		t.Fatalf("weighted copy has different edges")
+	}
+	for _, edge := range a.Edges() {
+		weightA, _ := a.Weight(edge[0], edge[1])
+		weightB, _ := b.Weight(edge[0], edge[1])
+		if weightA != weightB || weightA < 1 || weightA > 9 || weightA != float64(int(weightA)) {
+			t.Fatalf("edge %v weights %v and %v", edge, weightA, weightB)
+		}
+	}
+}
+
+func BenchmarkErdosRenyi(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		ErdosRenyi(10000, 0.001, int64(i))
+	}
+}
@@ -0,0 +1,248 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Mappings go from pattern vertex IDs to graph vertex IDs.
+
+type MatchOptions struct {
+	// Matched vertices must have equal values
+	Values bool
+	// Pattern vertices that are not adjacent must not be mapped to adjacent vertices,
+	// so matches are induced subgraphs rather than any subgraph with the pattern's edges
+	Induced bool
+}
+
+// VF2 search for the embeddings of pattern in g: mappings of the pattern vertices
+// to distinct vertices of g such that every pattern edge (self loops included)
+// is an edge of g. visit receives every mapping and may return false to stop.
+// Symmetric patterns match the same vertices once per automorphism.
+func VF2[T comparable](pattern, g *collections.Graph[T], options MatchOptions, visit func(mapping map[int]int) bool) {
+	state := newVF2State(pattern, g, options, false)
+	state.match(0, visit)
+}
+
+// Returns all the mappings VF2 finds, in search order
+func SubgraphMatches[T comparable](pattern, g *collections.Graph[T], options MatchOptions) []map[int]int {
+	matches := []map[int]int{}
+	VF2(pattern, g, options, func(mapping map[int]int) bool {
+		matches = append(matches, mapping)
+		return true
+	})
+	return matches
+}
+
+// Reports whether the graphs are isomorphic, and if so a mapping from the
+// vertices of g1 to those of g2 that preserves edges (and values if requested).
+// options.Induced is implied.
+func Isomorphic[T comparable](g1, g2 *collections.Graph[T], options MatchOptions) (map[int]int, bool) {
+	if g1.VertexCount() != g2.VertexCount() || g1.EdgeCount() != g2.EdgeCount() {
+		return nil, false
+	}
+	degrees := func(g *collections.Graph[T]) []int {
+		result := []int{}
+		for _, id := range g.VertexIDs() {
+			degree, _ := g.Degree(id)
+			result = append(result, degree)
+		}
+		slices.Sort(result)
+		return result
+	}
+	if !slices.Equal(degrees(g1), degrees(g2)) {
+		return nil, false
+	}
+
+	options.Induced = true
+	var result map[int]int
+	state := newVF2State(g1, g2, options, true)
+	state.match(0, func(mapping map[int]int) bool {
+		result = mapping
+		return false
+	})
+	return result, result != nil
+}
+
+type vf2State struct {
+	patternIDs, targetIDs []int
+	// Neighbours without self loops, in ascending order
+	pattern, target         [][]int
+	patternLoop, targetLoop []bool
+	sameValue               func(p, t int) bool
+	induced, isomorphism    bool
+	// Pattern vertices in matching order, each preceded by a neighbour if it has one
+	order, parent []int
+	// Matched partner or -1
+	core, inverse []int
+	// Depth at which a vertex joined the terminal set (unmatched neighbours of
+	// matched vertices) or became matched, 0 if neither
+	patternDepth, targetDepth []int
+}
+
+func newVF2State[T comparable](pattern, g *collections.Graph[T], options MatchOptions, isomorphism bool) *vf2State {
+	s := &vf2State{induced: options.Induced || isomorphism, isomorphism: isomorphism}
+	s.patternIDs, s.pattern, s.patternLoop = vf2Adjacency(pattern)
+	s.targetIDs, s.target, s.targetLoop = vf2Adjacency(g)
+	s.sameValue = func(p, t int) bool {
+		return !options.Values || pattern.Vertices[s.patternIDs[p]].Value == g.Vertices[s.targetIDs[t]].Value
+	}
+	s.core = make([]int, len(s.pattern))
+	for p := range s.core {
+		s.core[p] = -1
+	}
+	s.inverse = make([]int, len(s.target))
+	for t := range s.inverse {
+		s.inverse[t] = -1
+	}
+	s.patternDepth = make([]int, len(s.pattern))
+	s.targetDepth = make([]int, len(s.target))
+
+	// breadth-first from the highest degree vertex of every component, so that
+	// most vertices have a matched neighbour when their turn comes
+	added := make([]bool, len(s.pattern))
+	byDegree := make([]int, len(s.pattern))
+	for v := range byDegree {
+		byDegree[v] = v
+	}
+	slices.SortStableFunc(byDegree, func(a, b int) int {
+		return len(s.pattern[b]) - len(s.pattern[a])
+	})
+	for _, root := range byDegree {
+		if added[root] {
+			continue
+		}
+		added[root] = true
+		s.order = append(s.order, root)
+		s.parent = append(s.parent, -1)
+		for head := len(s.order) - 1; head < len(s.order); head++ {
+			for _, v := range s.pattern[s.order[head]] {
+				if !added[v] {
+					added[v] = true
+					s.order = append(s.order, v)
+					s.parent = append(s.parent, s.order[head])
+				}
+			}
+		}
+	}
+	return s
+}
+
+func vf2Adjacency[T comparable](g *collections.Graph[T]) ([]int, [][]int, []bool) {
+	dense := denseOf(g)
+	loops := make([]bool, len(dense.ids))
+	for v, neighbours := range dense.adjacency {
+		if i, found := slices.BinarySearch(neighbours, v); found {
+			loops[v] = true
+			dense.adjacency[v] = slices.Delete(neighbours, i, i+1)
+		}
+	}
+	return dense.ids, dense.adjacency, loops
+}
+
+// Returns false once visit asked to stop
+func (s *vf2State) match(depth int, visit func(map[int]int) bool) bool {
+	if depth == len(s.order) {
+		mapping := map[int]int{}
+		for p, t := range s.core {
+			mapping[s.patternIDs[p]] = s.targetIDs[t]
+		}
+		return visit(mapping)
+	}
+
+	p := s.order[depth]
+	var candidates []int
+	if s.parent[depth] != -1 {
+		candidates = s.target[s.core[s.parent[depth]]]
+	} else {
+		candidates = make([]int, len(s.target))
+		for t := range candidates {
+			candidates[t] = t
+		}
+	}
+	for _, t := range candidates {
+		if s.inverse[t] != -1 || !s.feasible(p, t) {
+			continue
+		}
+		s.add(p, t, depth+1)
+		if !s.match(depth+1, visit) {
+			return false
+		}
+		s.remove(p, t, depth+1)
+	}
+	return true
+}
+
+func (s *vf2State) feasible(p, t int) bool {
+	if !s.sameValue(p, t) {
+		return false
+	}
+	if s.patternLoop[p] && !s.targetLoop[t] || s.induced && s.targetLoop[t] && !s.patternLoop[p] {
+		return false
+	}
+	if len(s.target[t]) < len(s.pattern[p]) || s.isomorphism && len(s.target[t]) != len(s.pattern[p]) {
+		return false
+	}
+
+	// matched neighbours must correspond, the others are counted for look-ahead
+	matchedP, terminalP, newP := 0, 0, 0
+	for _, n := range s.pattern[p] {
+		switch {
+		case s.core[n] != -1:
+			if _, found := slices.BinarySearch(s.target[t], s.core[n]); !found {
+				return false
+			}
+			matchedP++
+		case s.patternDepth[n] > 0:
+			terminalP++
+		default:
+			newP++
+		}
+	}
+	matchedT, terminalT, newT := 0, 0, 0
+	for _, n := range s.target[t] {
+		switch {
+		case s.inverse[n] != -1:
+			matchedT++
+		case s.targetDepth[n] > 0:
+			terminalT++
+		default:
+			newT++
+		}
+	}
+
+	if terminalP > terminalT {
+		return false
+	}
+	if !s.induced {
+		return terminalP+newP <= terminalT+newT
+	}
+	if s.isomorphism {
+		return matchedP == matchedT && terminalP == terminalT && newP == newT
+	}
+	return matchedP == matchedT && newP <= newT
+}
+
+func (s *vf2State) add(p, t, depth int) {
+	s.core[p], s.inverse[t] = t, p
+	relabel(p, s.pattern, s.patternDepth, 0, depth)
+	relabel(t, s.target, s.targetDepth, 0, depth)
+}
+
+func (s *vf2State) remove(p, t, depth int) {
+	s.core[p], s.inverse[t] = -1, -1
+	relabel(p, s.pattern, s.patternDepth, depth, 0)
+	relabel(t, s.target, s.targetDepth, depth, 0)
+}
+
+// Moves v and its neighbours at depth from to depth to
+func relabel(v int, neighbours [][]int, depths []int, from, to int) {
+	if depths[v] == from {
+		depths[v] = to
+	}
+	for _, n := range neighbours[v] {
+		if depths[n] == from {
+			depths[n] = to
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package graphs
+
+import (
+	"math/rand"
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Copy of g with its vertices added in a random order
+func shuffled(g *collections.Graph[int], seed int64) (*collections.Graph[int], map[int]int) {
+	random := rand.New(rand.NewSource(seed))
+	ids := g.VertexIDs()
+	random.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+	relabelled, renamed := &collections.Graph[int]{}, map[int]int{}
+	for _, id := range ids {
+		renamed[id] = relabelled.AddVertex(g.Vertices[id].Value)
+	}
+	for _, edge := range g.Edges() {
+		relabelled.AddEdge(renamed[edge[0]], renamed[edge[1]])
+	}
+	return relabelled, renamed
+}
+
+// Checks that the mapping is injective and preserves edges, in both directions if induced
+func checkMapping(pattern, g *collections.Graph[int], mapping map[int]int, induced bool, t *testing.T) {
+	used := map[int]bool{}
+	for _, target := range mapping {
+		if used[target] {
+			t.Fatalf("mapping %v is not injective", mapping)
+		}
+		used[target] = true
+	}
+	for _, a := range pattern.VertexIDs() {
+		for _, b := range pattern.VertexIDs() {
+			if pattern.HasEdge(a, b) && !g.HasEdge(mapping[a], mapping[b]) ||
+				induced && !pattern.HasEdge(a, b) && g.HasEdge(mapping[a], mapping[b]) {
+				t.Fatalf("mapping %v does not preserve %v-%v", mapping, a, b)
+			}
+		}
+	}
+}
+
+// Number of mappings found by trying every injective assignment
+func bruteForceMatches(pattern, g *collections.Graph[int], induced bool) int {
+	patternIDs, ids := pattern.VertexIDs(), g.VertexIDs()
+	mapping, used := map[int]int{}, map[int]bool{}
+	var extend func(i int) int
+	extend = func(i int) int {
+		if i == len(patternIDs) {
+			for _, a := range patternIDs {
+				for _, b := range patternIDs {
+					if pattern.HasEdge(a, b) && !g.HasEdge(mapping[a], mapping[b]) ||
+						induced && !pattern.HasEdge(a, b) && g.HasEdge(mapping[a], mapping[b]) {
+						return 0
+					}
+				}
+			}
+			return 1
+		}
+		count := 0
+		for _, id := range ids {
+			if !used[id] {
+				used[id], mapping[patternIDs[i]] = true, id
+				count += extend(i + 1)
+				used[id] = false
+			}
+		}
+		return count
+	}
+	return extend(0)
+}
+
+func TestSubgraphMatches(t *testing.T) {
+	patterns := map[string]*collections.Graph[int]{
+		"triangle": Cycle(3),
+		"path":     Path(3),
+		"square":   Cycle(4),
+		"star":     Star(4),
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		g, _ := ErdosRenyi(7, 0.5, seed)
+		for name, pattern := range patterns {
+			for _, induced := range []bool{false, true} {
+				matches := SubgraphMatches(pattern, g, MatchOptions{Induced: induced})
+				for _, mapping := range matches {
+					checkMapping(pattern, g, mapping, induced, t)
+				}
+				if expected := bruteForceMatches(pattern, g, induced); len(matches) != expected {
+					t.Fatalf("seed %v, %v, induced %v: %v matches, expected %v", seed, name, induced, len(matches), expected)
+				}
+			}
+		}
+	}
+
+	// a triangle with a loop only matches triangles with a loop at the same place
+	pattern := Cycle(3)
+	pattern.AddEdge(1, 1)
+	g := Complete(4)
+	g.AddEdge(2, 2)
+	for _, mapping := range SubgraphMatches(pattern, g, MatchOptions{}) {
+		if mapping[1] != 2 {
+			t.Fatalf("looped vertex mapped by %v", mapping)
+		}
+	}
+
+	// stopping early and vertex values
+	count := 0
+	VF2(Path(2), Complete(5), MatchOptions{}, func(map[int]int) bool {
+		count++
+		return count < 4
+	})
+	if count != 4 {
+		t.Fatalf("enumeration went on after being stopped: %v matches", count)
+	}
+	labelled := Path(3)
+	labelled.Vertices[2].Value = 7
+	host := Cycle(6)
+	host.Vertices[5].Value = 7
+	matches := SubgraphMatches(labelled, host, MatchOptions{Values: true})
+	if len(matches) != 0 {
+		t.Fatalf("values ignored: %v", matches)
+	}
+	labelled.Vertices[1].Value, labelled.Vertices[3].Value = 3, 5
+	matches = SubgraphMatches(labelled, host, MatchOptions{Values: true})
+	if len(matches) != 1 || matches[0][1] != 4 || matches[0][2] != 5 || matches[0][3] != 6 {
+		t.Fatalf("value matches %v", matches)
+	}
+}
+
+func TestIsomorphic(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g, _ := ErdosRenyi(12, 0.3, seed)
+		relabelled, renamed := shuffled(g, seed)
+		mapping, isomorphic := Isomorphic(g, relabelled, MatchOptions{})
+		if !isomorphic {
+			t.Fatalf("seed %v: relabelled relabelled %v not isomorphic", seed, renamed)
+		}
+		checkMapping(g, relabelled, mapping, true, t)
+		if _, isomorphic := Isomorphic(g, relabelled, MatchOptions{Values: true}); !isomorphic {
+			t.Fatalf("seed %v: relabelled relabelled with the same values not isomorphic", seed)
+		}
+
+		small, _ := ErdosRenyi(6, 0.5, seed)
+		other, _ := ErdosRenyi(6, 0.5, seed+100)
+		_, isomorphic = Isomorphic(small, other, MatchOptions{})
+		expected := small.EdgeCount() == other.EdgeCount() && bruteForceMatches(small, other, true) > 0
+		if isomorphic != expected {
+			t.Fatalf("seed %v: isomorphic %v, expected %v", seed, isomorphic, expected)
+		}
+	}
+
+	// same degrees, different structure
+	twoTriangles := Cycle(3)
+	for i := 4; i <= 6; i++ {
+		twoTriangles.AddVertex(i - 1)
+	}
+	twoTriangles.AddEdge(4, 5)
+	twoTriangles.AddEdge(5, 6)
+	twoTriangles.AddEdge(6, 4)
+	if _, isomorphic := Isomorphic(twoTriangles, Cycle(6), MatchOptions{}); isomorphic {
+		t.Fatalf("two triangles isomorphic to a hexagon")
+	}
+	if _, isomorphic := Isomorphic(Path(4), Path(4), MatchOptions{Values: true}); !isomorphic {
+		t.Fatalf("path not isomorphic to itself")
+	}
+	reversed := Path(4)
+	reversed.Vertices[1].Value, reversed.Vertices[4].Value = 3, 0
+	reversed.Vertices[2].Value, reversed.Vertices[3].Value = 2, 1
+	if mapping, _ := Isomorphic(Path(4), reversed, MatchOptions{Values: true}); mapping[1] != 4 {
+		t.Fatalf("values ignored by %v", mapping)
+	}
+}
+
+func BenchmarkIsomorphic(b *testing.B) {
+	g, _ := ErdosRenyi(300, 0.05, 0)
+	relabelled, _ := shuffled(g, 0)
+	for i := 0; i < b.N; i++ {
+		Isomorphic(g, relabelled, MatchOptions{})
+	}
+}
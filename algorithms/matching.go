@@ -0,0 +1,277 @@
+package graphs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+var ErrNoAssignment = errors.New("No complete assignment exists")
+
+// Returned when a two-colouring is requested for a graph that is not bipartite
+type OddCycleError struct {
+	// Vertex IDs along an odd cycle, the last vertex leads back to the first
+	Cycle []int
+}
+
+func (e *OddCycleError) Error() string {
+	return fmt.Sprintf("Graph is not bipartite, odd cycle: %v", e.Cycle)
+}
+
+// Colours the vertices of an undirected graph with colours 0 and 1 so that
+// no edge joins two vertices of the same colour. The smallest vertex ID of
+// every connected component is coloured 0.
+// Returns an *OddCycleError if the graph is not bipartite.
+func TwoColouring[T comparable](g collections.Grapher[T]) (map[int]int, error) {
+	if g.Directed() {
+		return nil, ErrDirected
+	}
+	colour := map[int]int{}
+	parent := map[int]int{}
+	for _, root := range g.VertexIDs() {
+		if _, ok := colour[root]; ok {
+			continue
+		}
+		colour[root] = 0
+		queue := &collections.Queue[int]{}
+		queue.Push(root)
+		for !queue.Empty() {
+			id, _ := queue.Pop()
+			for _, neighbour := range g.Successors(id) {
+				if c, ok := colour[neighbour]; !ok {
+					colour[neighbour] = 1 - colour[id]
+					parent[neighbour] = id
+					queue.Push(neighbour)
+				} else if c == colour[id] {
+					return nil, &OddCycleError{oddCycle(parent, root, id, neighbour)}
+				}
+			}
+		}
+	}
+	return colour, nil
+}
+
+func IsBipartite[T comparable](g collections.Grapher[T]) bool {
+	_, err := TwoColouring(g)
+	return err == nil
+}
+
+// Joins the BFS tree paths of a and b at their lowest common ancestor
+func oddCycle(parent map[int]int, root, a, b int) []int {
+	pathTo := func(id int) []int {
+		path := []int{id}
+		for id != root {
+			id = parent[id]
+			path = append(path, id)
+		}
+		slices.Reverse(path)
+		return path
+	}
+	pathA, pathB := pathTo(a), pathTo(b)
+	common := 0
+	for common+1 < len(pathA) && common+1 < len(pathB) && pathA[common+1] == pathB[common+1] {
+		common++
+	}
+	cycle := slices.Clone(pathA[common:])
+	for i := len(pathB) - 1; i > common; i-- {
+		cycle = append(cycle, pathB[i])
+	}
+	return cycle
+}
+
+// Maximum cardinality matching of a bipartite graph (Hopcroft-Karp), O(|E|*sqrt(|V|)).
+// The sides are given by TwoColouring; every matched pair is returned as
+// {colour 0 vertex, colour 1 vertex}, sorted by the first ID.
+func HopcroftKarp[T comparable](g collections.Grapher[T]) ([][2]int, error) {
+	colour, err := TwoColouring(g)
+	if err != nil {
+		return nil, err
+	}
+	left := []int{}
+	for _, id := range g.VertexIDs() {
+		if colour[id] == 0 {
+			left = append(left, id)
+		}
+	}
+
+	// partner of every matched vertex, unmatched ones are absent
+	mate := map[int]int{}
+	distance := map[int]int{}
+
+	// length of the shortest augmenting paths in the current phase
+	shortest := math.MaxInt
+
+	// Layers the free left vertices and the alternating paths leaving them.
+	// Returns true if an augmenting path exists.
+	layer := func() bool {
+		queue := &collections.Queue[int]{}
+		for _, u := range left {
+			if _, matched := mate[u]; !matched {
+				distance[u] = 0
+				queue.Push(u)
+				continue
+			}
+			distance[u] = math.MaxInt
+		}
+		shortest = math.MaxInt
+		for !queue.Empty() {
+			u, _ := queue.Pop()
+			if distance[u] >= shortest {
+				continue
+			}
+			for _, v := range g.Successors(u) {
+				next, matched := mate[v]
+				if !matched {
+					shortest = min(shortest, distance[u]+1)
+					continue
+				}
+				if distance[next] == math.MaxInt {
+					distance[next] = distance[u] + 1
+					queue.Push(next)
+				}
+			}
+		}
+		return shortest != math.MaxInt
+	}
+
+	var augment func(u int) bool
+	augment = func(u int) bool {
+		for _, v := range g.Successors(u) {
+			next, matched := mate[v]
+			if (!matched && distance[u]+1 == shortest) ||
+				(matched && distance[next] == distance[u]+1 && augment(next)) {
+				mate[u], mate[v] = v, u
+				return true
+			}
+		}
+		distance[u] = math.MaxInt
+		return false
+	}
+
+	for layer() {
+		for _, u := range left {
+			if _, matched := mate[u]; !matched {
+				augment(u)
+			}
+		}
+	}
+
+	pairs := [][2]int{}
+	for _, u := range left {
+		if v, matched := mate[u]; matched {
+			pairs = append(pairs, [2]int{u, v})
+		}
+	}
+	return pairs, nil
+}
+
+// Minimum cost assignment over a weighted bipartite graph (Hungarian algorithm), O(n^2*m).
+// Every vertex of the smaller side is assigned to a distinct neighbour on the
+// other side, minimizing the total weight of the used edges.
+// The sides are given by TwoColouring, isolated vertices belong to neither side.
+// Every pair is returned as {colour 0 vertex, colour 1 vertex}, sorted by the first ID.
+// Returns ErrNoAssignment if the smaller side cannot be fully assigned.
+func MinCostAssignment[T comparable](g *collections.WGraph[T]) ([][2]int, float64, error) {
+	colour, err := TwoColouring(g)
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, columns := []int{}, []int{}
+	for _, id := range g.VertexIDs() {
+		if len(g.Vertices[id].Edges) == 0 {
+			continue
+		}
+		if colour[id] == 0 {
+			rows = append(rows, id)
+			continue
+		}
+		columns = append(columns, id)
+	}
+	transposed := len(rows) > len(columns)
+	if transposed {
+		rows, columns = columns, rows
+	}
+	n, m := len(rows), len(columns)
+	if n == 0 {
+		return [][2]int{}, 0, nil
+	}
+
+	// Missing edges cost more than any complete assignment over existing edges
+	forbidden := 1.0
+	for _, vertex := range g.Vertices {
+		for _, edge := range vertex.Edges {
+			forbidden += math.Abs(edge.Weight)
+		}
+	}
+	cost := func(i, j int) float64 {
+		if edge, ok := g.Vertices[rows[i-1]].Edges[columns[j-1]]; ok {
+			return edge.Weight
+		}
+		return forbidden
+	}
+
+	// Potentials u (rows) and v (columns), p[j] is the row assigned to column j.
+	// Index 0 is a virtual column used while extending the assignment.
+	u, v := make([]float64, n+1), make([]float64, m+1)
+	p, way := make([]int, m+1), make([]int, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minimum := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minimum {
+			minimum[j] = math.Inf(1)
+		}
+		for p[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if current := cost(i0, j) - u[i0] - v[j]; current < minimum[j] {
+					minimum[j], way[j] = current, j0
+				}
+				if minimum[j] < delta {
+					delta, j1 = minimum[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+					continue
+				}
+				minimum[j] -= delta
+			}
+			j0 = j1
+		}
+		// flip the alternating path back to the virtual column
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	pairs := [][2]int{}
+	total := 0.0
+	for j := 1; j <= m; j++ {
+		if p[j] == 0 {
+			continue
+		}
+		if _, ok := g.Vertices[rows[p[j]-1]].Edges[columns[j-1]]; !ok {
+			return nil, 0, ErrNoAssignment
+		}
+		total += cost(p[j], j)
+		pair := [2]int{rows[p[j]-1], columns[j-1]}
+		if transposed {
+			pair[0], pair[1] = pair[1], pair[0]
+		}
+		pairs = append(pairs, pair)
+	}
+	slices.SortFunc(pairs, compareEdges)
+	return pairs, total, nil
+}
@@ -0,0 +1,168 @@
+package graphs
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+// Random bipartite graph with vertices 1..left on one side and the rest on the other
+func randomBipartite(left, right int, p float64, seed int64) *collections.WGraph[int] {
+	random := rand.New(rand.NewSource(seed))
+	g := &collections.WGraph[int]{}
+	for i := 1; i <= left+right; i++ {
+		g.AddVertex(i)
+	}
+	for a := 1; a <= left; a++ {
+		for b := left + 1; b <= left+right; b++ {
+			if random.Float64() < p {
+				g.AddEdge(a, b, float64(random.Intn(50)))
+			}
+		}
+	}
+	return g
+}
+
+func TestTwoColouring(t *testing.T) {
+	g := &collections.Graph[int]{}
+	for i := 1; i <= 6; i++ {
+		g.AddVertex(i)
+	}
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 1)
+	g.AddEdge(5, 6)
+
+	colour, err := TwoColouring[int](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range g.VertexIDs() {
+		for _, b := range g.Successors(a) {
+			if colour[a] == colour[b] {
+				t.Fatalf("edge %v-%v joins equal colours: %v", a, b, colour)
+			}
+		}
+	}
+	if colour[1] != 0 || colour[5] != 0 {
+		t.Fatalf("component roots are not coloured 0: %v", colour)
+	}
+
+	g.AddEdge(2, 4)
+	_, err = TwoColouring[int](g)
+	var oddErr *OddCycleError
+	if !errors.As(err, &oddErr) {
+		t.Fatalf("expected an OddCycleError, got %v", err)
+	}
+	cycle := oddErr.Cycle
+	if len(cycle)%2 == 0 {
+		t.Fatalf("cycle %v is even", cycle)
+	}
+	for i := range cycle {
+		if _, ok := g.Vertices[cycle[i]].Edges[cycle[(i+1)%len(cycle)]]; !ok {
+			t.Fatalf("cycle %v is not a cycle of the graph", cycle)
+		}
+	}
+	if IsBipartite[int](g) {
+		t.Fatalf("graph with a triangle reported bipartite")
+	}
+}
+
+func TestHopcroftKarp(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		g := randomBipartite(12, 9, 0.2, seed)
+		pairs, err := HopcroftKarp[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		used := map[int]bool{}
+		for _, pair := range pairs {
+			if _, ok := g.Vertices[pair[0]].Edges[pair[1]]; !ok || used[pair[0]] || used[pair[1]] {
+				t.Fatalf("seed %v: invalid matching %v", seed, pairs)
+			}
+			used[pair[0]], used[pair[1]] = true, true
+		}
+
+		// unit capacity flow network from a super source to a super sink
+		network := &collections.WDiGraph[int]{}
+		for range g.VertexIDs() {
+			network.AddVertex(0)
+		}
+		source, sink := network.AddVertex(0), network.AddVertex(0)
+		for a := 1; a <= 12; a++ {
+			network.AddEdge(source, a, 1)
+			for _, b := range g.Successors(a) {
+				network.AddEdge(a, b, 1)
+			}
+		}
+		for b := 13; b <= 21; b++ {
+			network.AddEdge(b, sink, 1)
+		}
+		flow, _ := MaxFlow(network, source, sink)
+		if float64(len(pairs)) != flow.Value {
+			t.Fatalf("seed %v: matching size %v, max flow %v", seed, len(pairs), flow.Value)
+		}
+	}
+
+	// a vertex with ID 0 is matched like any other
+	g := &collections.Graph[int]{Vertices: map[int]*collections.Vertex[int]{}}
+	for id := 0; id < 4; id++ {
+		g.Vertices[id] = &collections.Vertex[int]{Edges: map[int]*collections.Vertex[int]{}}
+	}
+	g.Counter = 3
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 1)
+	g.AddEdge(2, 3)
+	if pairs, err := HopcroftKarp[int](g); err != nil || !slices.Equal(pairs, [][2]int{{0, 1}, {2, 3}}) {
+		t.Fatalf("matching %v with vertex 0: %v", pairs, err)
+	}
+}
+
+// Brute-force minimum cost of assigning rows 1..n to distinct columns
+func bruteForceAssignment(g *collections.WGraph[int], row int, rows int, used map[int]bool) float64 {
+	if row > rows {
+		return 0
+	}
+	best := math.Inf(1)
+	for column, edge := range g.Vertices[row].Edges {
+		if used[column] {
+			continue
+		}
+		used[column] = true
+		best = min(best, edge.Weight+bruteForceAssignment(g, row+1, rows, used))
+		used[column] = false
+	}
+	return best
+}
+
+func TestMinCostAssignment(t *testing.T) {
+	for seed := int64(0); seed < 40; seed++ {
+		g := randomBipartite(5, 7, 0.6, seed)
+		expected := bruteForceAssignment(g, 1, 5, map[int]bool{})
+
+		pairs, total, err := MinCostAssignment(g)
+		if math.IsInf(expected, 1) {
+			if err != ErrNoAssignment {
+				t.Fatalf("seed %v: expected ErrNoAssignment, got %v", seed, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("seed %v: %v", seed, err)
+		}
+		if total != expected || len(pairs) != 5 {
+			t.Fatalf("seed %v: assignment %v costs %v, expected %v", seed, pairs, total, expected)
+		}
+		sum := 0.0
+		for _, pair := range pairs {
+			sum += g.Vertices[pair[0]].Edges[pair[1]].Weight
+		}
+		if sum != total {
+			t.Fatalf("seed %v: pairs %v cost %v, reported %v", seed, pairs, sum, total)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package graphs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+var ErrNoPath = errors.New("No path satisfies the constraints")
+
+type WeightedPath struct {
+	// Vertex IDs from the source to the target
+	Vertices []int
+	Cost     float64
+}
+
+// Yen's algorithm: up to k loopless paths from s to t in order of increasing
+// cost, fewer if the graph does not have that many. Equal costs are ordered by
+// the number of edges and then by the vertex IDs along the path. As Yen's
+// algorithm finds equal cost paths in no particular order, every path of the
+// k-th path's cost is found before they are sorted. Every path is found by
+// Dijkstra searches, O(m*|V|*(|E|+|V|)*log(|V|)) for the m paths found.
+func KShortestPaths[T comparable](g collections.WeightedGrapher[T], s, t, k int) ([]WeightedPath, error) {
+	for _, id := range []int{s, t} {
+		if !g.HasVertex(id) {
+			return nil, &collections.UnknownVertexError{ID: id}
+		}
+	}
+	dense := denseOfWeighted(g)
+	source, target := dense.index[s], dense.index[t]
+	result := []WeightedPath{}
+	if k <= 0 {
+		return result, nil
+	}
+
+	first, err := dense.dijkstraPaths(source)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(first.distance[target], 1) {
+		return result, nil
+	}
+	firstPath := first.pathTo(target)
+	found := []weightedPath{{firstPath, dense.pathCost(firstPath)}}
+	candidates := collections.NewPriorityQueue(compareCandidates)
+	seen := map[string]bool{pathKey(firstPath): true}
+
+	for {
+		previous := found[len(found)-1].vertices
+		for i := 0; i < len(previous)-1; i++ {
+			spur, root := previous[i], previous[:i+1]
+			// leave the root path, and every edge known paths with the same root take next
+			blockedVertex := make([]bool, len(dense.ids))
+			for _, v := range root[:i] {
+				blockedVertex[v] = true
+			}
+			blockedEdge := map[[2]int]bool{}
+			for _, path := range found {
+				if len(path.vertices) > i+1 && slices.Equal(path.vertices[:i+1], root) {
+					blockedEdge[[2]int{spur, path.vertices[i+1]}] = true
+				}
+			}
+			paths, err := dense.dijkstra(spur, func(u, entry int) bool {
+				v := dense.adjacency[u][entry]
+				return blockedVertex[v] || u == spur && blockedEdge[[2]int{u, v}]
+			}, nil)
+			if err != nil {
+				return nil, err
+			}
+			if math.IsInf(paths.distance[target], 1) {
+				continue
+			}
+
+			path := append(slices.Clone(root[:i]), paths.pathTo(target)...)
+			if key := pathKey(path); !seen[key] {
+				seen[key] = true
+				candidates.Push(weightedPath{path, dense.pathCost(path)})
+			}
+		}
+		// the candidates hold the next cheapest path, so a dearer one means that
+		// the paths of the k-th path's cost are all found
+		next, err := candidates.Peek()
+		if err != nil || len(found) >= k && next.cost > found[k-1].cost+pathEpsilon {
+			break
+		}
+		candidates.Pop()
+		found = append(found, next)
+	}
+
+	slices.SortStableFunc(found, compareCandidates)
+	for _, path := range found[:min(k, len(found))] {
+		result = append(result, dense.weightedPath(path.vertices))
+	}
+	return result, nil
+}
+
+type PathConstraints struct {
+	// Largest number of edges, unlimited if zero
+	MaxHops int
+	// Secondary weight of the edge between two vertex IDs, such as latency when
+	// the edge weight is a price. Must not be negative.
+	Resource func(from, to int) float64
+	// Largest total secondary weight of the path, used if Resource is set
+	MaxResource float64
+}
+
+// Cheapest path from s to t within the hop and secondary weight limits, found by
+// a label-setting search that keeps every Pareto optimal (cost, resource, hops)
+// label per vertex. Dijkstra distances to t bound the remaining cost and resource.
+// Returns ErrNoPath if no path satisfies the constraints.
+func ConstrainedShortestPath[T comparable](g collections.WeightedGrapher[T], s, t int, constraints PathConstraints) (WeightedPath, error) {
+	for _, id := range []int{s, t} {
+		if !g.HasVertex(id) {
+			return WeightedPath{}, &collections.UnknownVertexError{ID: id}
+		}
+	}
+	if constraints.MaxHops < 0 || constraints.Resource != nil && constraints.MaxResource < 0 {
+		return WeightedPath{}, ErrInvalidParameter
+	}
+	dense := denseOfWeighted(g)
+	source, target := dense.index[s], dense.index[t]
+
+	resources := make([][]float64, len(dense.ids))
+	for u, successors := range dense.adjacency {
+		resources[u] = make([]float64, len(successors))
+		for k, v := range successors {
+			if constraints.Resource != nil {
+				resources[u][k] = constraints.Resource(dense.ids[u], dense.ids[v])
+			}
+			if resources[u][k] < 0 {
+				return WeightedPath{}, ErrNegativeWeight
+			}
+		}
+	}
+
+	// lower bounds of what is left to reach the target, from searches over the reversed graph
+	reversed := dense.reversed(dense.weights)
+	costBound, err := reversed.dijkstraPaths(target)
+	if err != nil {
+		return WeightedPath{}, err
+	}
+	resourceBound, err := dense.reversed(resources).dijkstraPaths(target)
+	if err != nil {
+		return WeightedPath{}, err
+	}
+	hopBound := reversed.breadthFirstPaths(target)
+	feasible := func(l *pathLabel) bool {
+		return !math.IsInf(costBound.distance[l.vertex], 1) &&
+			(constraints.MaxHops == 0 || float64(l.hops)+hopBound.distance[l.vertex] <= float64(constraints.MaxHops)) &&
+			(constraints.Resource == nil || l.resource+resourceBound.distance[l.vertex] <= constraints.MaxResource+pathEpsilon)
+	}
+
+	start := &pathLabel{vertex: source}
+	if !feasible(start) {
+		return WeightedPath{}, ErrNoPath
+	}
+	labels := make([][]*pathLabel, len(dense.ids))
+	labels[source] = []*pathLabel{start}
+	queue := collections.NewPriorityQueue(compareLabels)
+	queue.Push(labelItem{start, costBound.distance[source]})
+	for !queue.Empty() {
+		item, _ := queue.Pop()
+		l := item.label
+		if l.dominated {
+			continue
+		}
+		if l.vertex == target {
+			path := []int{}
+			for ; l != nil; l = l.previous {
+				path = append(path, l.vertex)
+			}
+			slices.Reverse(path)
+			return dense.weightedPath(path), nil
+		}
+
+		for k, v := range dense.adjacency[l.vertex] {
+			next := &pathLabel{
+				vertex:   v,
+				cost:     l.cost + dense.weights[l.vertex][k],
+				resource: l.resource + resources[l.vertex][k],
+				hops:     l.hops + 1,
+				previous: l,
+			}
+			if !feasible(next) || !addLabel(labels, next) {
+				continue
+			}
+			queue.Push(labelItem{next, next.cost + costBound.distance[v]})
+		}
+	}
+	return WeightedPath{}, ErrNoPath
+}
+
+// Partial path of the constrained search
+type pathLabel struct {
+	vertex         int
+	cost, resource float64
+	hops           int
+	previous       *pathLabel
+	dominated      bool
+}
+
+func (a *pathLabel) dominates(b *pathLabel) bool {
+	return a.cost <= b.cost && a.resource <= b.resource && a.hops <= b.hops
+}
+
+// Adds the label to the Pareto set of its vertex unless an existing label
+// dominates it, dropping the labels it dominates
+func addLabel(labels [][]*pathLabel, l *pathLabel) bool {
+	set := labels[l.vertex]
+	for _, other := range set {
+		if other.dominates(l) {
+			return false
+		}
+	}
+	kept := set[:0]
+	for _, other := range set {
+		if l.dominates(other) {
+			other.dominated = true
+		} else {
+			kept = append(kept, other)
+		}
+	}
+	labels[l.vertex] = append(kept, l)
+	return true
+}
+
+// Copy of the graph with every edge reversed, weighted by the given weights
+// of the original adjacency entries
+func (g *denseGraph) reversed(weights [][]float64) *denseGraph {
+	n := len(g.ids)
+	result := &denseGraph{ids: g.ids, index: g.index, adjacency: make([][]int, n), weights: make([][]float64, n)}
+	for u, successors := range g.adjacency {
+		for k, v := range successors {
+			result.adjacency[v] = append(result.adjacency[v], u)
+			result.weights[v] = append(result.weights[v], weights[u][k])
+		}
+	}
+	return result
+}
+
+func (g *denseGraph) pathCost(path []int) float64 {
+	cost := 0.0
+	for i := 1; i < len(path); i++ {
+		k, _ := slices.BinarySearch(g.adjacency[path[i-1]], path[i])
+		cost += g.weights[path[i-1]][k]
+	}
+	return cost
+}
+
+func (g *denseGraph) weightedPath(path []int) WeightedPath {
+	result := WeightedPath{Vertices: make([]int, len(path)), Cost: g.pathCost(path)}
+	for i, v := range path {
+		result.Vertices[i] = g.ids[v]
+	}
+	return result
+}
+
+func pathKey(path []int) string {
+	return fmt.Sprint(path)
+}
+
+type weightedPath struct {
+	vertices []int
+	cost     float64
+}
+
+// Orders candidate paths by cost, length and then vertices
+func compareCandidates(a, b weightedPath) int {
+	if math.Abs(a.cost-b.cost) > pathEpsilon {
+		return cmp.Compare(a.cost, b.cost)
+	}
+	return cmp.Or(cmp.Compare(len(a.vertices), len(b.vertices)), slices.Compare(a.vertices, b.vertices))
+}
+
+type labelItem struct {
+	label *pathLabel
+	// Cost so far plus the lower bound of the remaining cost
+	estimate float64
+}
+
+// Orders labels by estimated total cost, then by hops
+func compareLabels(a, b labelItem) int {
+	return cmp.Or(cmp.Compare(a.estimate, b.estimate), cmp.Compare(a.label.hops, b.label.hops))
+}
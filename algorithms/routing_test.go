@@ -0,0 +1,194 @@
+package graphs
+
+import (
+	"cmp"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+// All simple paths from s to t with their costs, cheapest first
+func allSimplePaths(g collections.WeightedGrapher[int], s, t int) []WeightedPath {
+	paths := []WeightedPath{}
+	visited := map[int]bool{s: true}
+	path := []int{s}
+	var extend func(cost float64)
+	extend = func(cost float64) {
+		last := path[len(path)-1]
+		if last == t {
+			paths = append(paths, WeightedPath{slices.Clone(path), cost})
+			return
+		}
+		for _, next := range g.Successors(last) {
+			if !visited[next] {
+				weight, _ := g.Weight(last, next)
+				visited[next] = true
+				path = append(path, next)
+				extend(cost + weight)
+				path = path[:len(path)-1]
+				visited[next] = false
+			}
+		}
+	}
+	extend(0)
+	// by cost, then length, then vertex IDs, as KShortestPaths promises
+	slices.SortFunc(paths, func(a, b WeightedPath) int {
+		return cmp.Or(
+			cmp.Compare(a.Cost, b.Cost),
+			cmp.Compare(len(a.Vertices), len(b.Vertices)),
+			slices.Compare(a.Vertices, b.Vertices),
+		)
+	})
+	return paths
+}
+
+func checkPath(g collections.WeightedGrapher[int], path WeightedPath, s, t int, test *testing.T) {
+	if path.Vertices[0] != s || path.Vertices[len(path.Vertices)-1] != t {
+		test.Fatalf("path %v does not run from %v to %v", path, s, t)
+	}
+	cost := 0.0
+	visited := map[int]bool{}
+	for i, id := range path.Vertices {
+		if visited[id] {
+			test.Fatalf("path %v has a loop", path)
+		}
+		visited[id] = true
+		if i > 0 {
+			weight, err := g.Weight(path.Vertices[i-1], id)
+			if err != nil {
+				test.Fatalf("path %v: %v", path, err)
+			}
+			cost += weight
+		}
+	}
+	if !closeTo(cost, path.Cost) {
+		test.Fatalf("path %v costs %v", path, cost)
+	}
+}
+
+func TestKShortestPaths(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		sparse, _ := ErdosRenyi(9, 0.4, seed)
+		g := Weighted(sparse, IntegerWeights(1, 5), seed)
+		expected := allSimplePaths(g, 1, 9)
+		paths, err := KShortestPaths[int](g, 1, 9, 12)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paths) != min(12, len(expected)) {
+			t.Fatalf("seed %v: %v paths, expected %v", seed, len(paths), min(12, len(expected)))
+		}
+		distinct := map[string]bool{}
+		for i, path := range paths {
+			checkPath(g, path, 1, 9, t)
+			if !closeTo(path.Cost, expected[i].Cost) {
+				t.Fatalf("seed %v: path %v is %v, expected cost %v", seed, i, path, expected[i].Cost)
+			}
+			distinct[pathKey(path.Vertices)] = true
+		}
+		if len(distinct) != len(paths) {
+			t.Fatalf("seed %v: repeated paths %v", seed, paths)
+		}
+	}
+
+	directed := &collections.WDiGraph[int]{}
+	for i := 0; i < 4; i++ {
+		directed.AddVertex(i)
+	}
+	directed.AddEdge(1, 2, 1)
+	directed.AddEdge(2, 4, 1)
+	directed.AddEdge(1, 3, 2)
+	directed.AddEdge(3, 4, 2)
+	directed.AddEdge(4, 1, 1)
+	paths, _ := KShortestPaths[int](directed, 1, 4, 5)
+	if len(paths) != 2 || paths[0].Cost != 2 || paths[1].Cost != 4 {
+		t.Fatalf("directed paths %v", paths)
+	}
+	if paths, _ := KShortestPaths[int](directed, 4, 4, 3); len(paths) != 1 || len(paths[0].Vertices) != 1 {
+		t.Fatalf("paths from a vertex to itself %v", paths)
+	}
+	if _, err := KShortestPaths[int](directed, 1, 5, 3); err == nil {
+		t.Fatalf("unknown target accepted")
+	}
+}
+
+func TestKShortestPathsOrder(t *testing.T) {
+	// small integer weights make many paths of equal cost
+	for seed := int64(0); seed < 100; seed++ {
+		dense, _ := ErdosRenyi(9, 0.5, seed)
+		g := Weighted(dense, IntegerWeights(1, 2), seed)
+		expected := allSimplePaths(g, 1, 9)
+		paths, err := KShortestPaths[int](g, 1, 9, 15)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = expected[:min(15, len(expected))]
+		if len(paths) != len(expected) {
+			t.Fatalf("seed %v: %v paths, expected %v", seed, len(paths), len(expected))
+		}
+		for i, path := range paths {
+			if !slices.Equal(path.Vertices, expected[i].Vertices) {
+				t.Fatalf("seed %v: path %v is %v, expected %v", seed, i, path.Vertices, expected[i].Vertices)
+			}
+		}
+	}
+}
+
+func TestConstrainedShortestPath(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		sparse, _ := ErdosRenyi(9, 0.4, seed)
+		g := Weighted(sparse, IntegerWeights(1, 5), seed)
+		// secondary weight unrelated to the primary one
+		latency := func(a, b int) float64 {
+			return float64((a*7+b*7)%5 + 1)
+		}
+		for _, constraints := range []PathConstraints{
+			{},
+			{MaxHops: 2},
+			{MaxHops: 3},
+			{Resource: latency, MaxResource: 6},
+			{MaxHops: 4, Resource: latency, MaxResource: 9},
+		} {
+			best := math.Inf(1)
+			for _, path := range allSimplePaths(g, 1, 9) {
+				resource := 0.0
+				for i := 1; i < len(path.Vertices); i++ {
+					resource += latency(path.Vertices[i-1], path.Vertices[i])
+				}
+				hops := len(path.Vertices) - 1
+				if (constraints.MaxHops == 0 || hops <= constraints.MaxHops) &&
+					(constraints.Resource == nil || resource <= constraints.MaxResource) {
+					best = min(best, path.Cost)
+				}
+			}
+
+			path, err := ConstrainedShortestPath[int](g, 1, 9, constraints)
+			if math.IsInf(best, 1) {
+				if err != ErrNoPath {
+					t.Fatalf("seed %v, hops %v, resource %v: expected ErrNoPath, got %v %v", seed, constraints.MaxHops, constraints.MaxResource, path, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPath(g, path, 1, 9, t)
+			if !closeTo(path.Cost, best) {
+				t.Fatalf("seed %v, hops %v, resource %v: %v, expected cost %v", seed, constraints.MaxHops, constraints.MaxResource, path, best)
+			}
+		}
+	}
+
+	g := Weighted(Path(3), ConstantWeights(1), 0)
+	if _, err := ConstrainedShortestPath[int](g, 1, 3, PathConstraints{Resource: func(int, int) float64 { return -1 }}); err != ErrNegativeWeight {
+		t.Fatalf("expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func BenchmarkKShortestPaths(b *testing.B) {
+	g := Weighted(Grid(20, 20), UniformWeights(1, 2), 0)
+	for i := 0; i < b.N; i++ {
+		KShortestPaths[int](g, 1, 400, 10)
+	}
+}
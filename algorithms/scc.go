@@ -0,0 +1,163 @@
+package graphs
+
+import (
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Tarjan's strongly connected components algorithm.
+// Returns the component index of every vertex ID, and the member vertex IDs of
+// every component. Components are numbered in topological order of the
+// condensation, so an edge between components always leads to a higher index.
+func TarjanSCC[T comparable](g collections.Grapher[T]) (map[int]int, [][]int) {
+	index, low := map[int]int{}, map[int]int{}
+	onStack := map[int]bool{}
+	members := []int{}
+	components := [][]int{}
+	stack := &collections.Stack[*dfsFrame]{}
+
+	visit := func(id int) {
+		index[id] = len(index)
+		low[id] = index[id]
+		members = append(members, id)
+		onStack[id] = true
+		stack.Push(&dfsFrame{id, g.Successors(id), 0})
+	}
+
+	for _, root := range g.VertexIDs() {
+		if _, ok := index[root]; ok {
+			continue
+		}
+		visit(root)
+
+		for !stack.Empty() {
+			frame, _ := stack.Peek()
+			if frame.next < len(frame.successors) {
+				successor := frame.successors[frame.next]
+				frame.next++
+				if _, ok := index[successor]; !ok {
+					visit(successor)
+				} else if onStack[successor] {
+					low[frame.id] = min(low[frame.id], index[successor])
+				}
+				continue
+			}
+
+			stack.Pop()
+			id := frame.id
+			if low[id] == index[id] {
+				// id is the root of a component, which is on top of the member stack
+				i := len(members) - 1
+				for members[i] != id {
+					i--
+				}
+				component := slices.Clone(members[i:])
+				members = members[:i]
+				for _, member := range component {
+					onStack[member] = false
+				}
+				slices.Sort(component)
+				components = append(components, component)
+			}
+			if parent, err := stack.Peek(); err == nil {
+				low[parent.id] = min(low[parent.id], low[id])
+			}
+		}
+	}
+
+	// Tarjan's algorithm completes sink components first
+	slices.Reverse(components)
+	return membership(components), components
+}
+
+// Kosaraju's strongly connected components algorithm.
+// Returns the component index of every vertex ID, and the member vertex IDs of
+// every component. Components are numbered in topological order of the
+// condensation, so an edge between components always leads to a higher index.
+func KosarajuSCC[T comparable](g collections.Grapher[T]) (map[int]int, [][]int) {
+	finished := make([]int, 0, g.VertexCount())
+	visited := map[int]bool{}
+	for _, root := range g.VertexIDs() {
+		depthFirst(root, g.Successors, visited, func(id int) {
+			finished = append(finished, id)
+		})
+	}
+
+	// Searching the transposed graph in reverse finishing order
+	// confines every search tree to a single component
+	components := [][]int{}
+	visited = map[int]bool{}
+	for i := len(finished) - 1; i >= 0; i-- {
+		if visited[finished[i]] {
+			continue
+		}
+		component := []int{}
+		depthFirst(finished[i], g.Predecessors, visited, func(id int) {
+			component = append(component, id)
+		})
+		slices.Sort(component)
+		components = append(components, component)
+	}
+	return membership(components), components
+}
+
+// Contracts every strongly connected component of g into a single vertex.
+// The result is a DAG whose vertex IDs are the component index + 1,
+// and whose vertex values are the smallest member ID of each component.
+// Also returns the condensation vertex ID of every vertex of g.
+func Condense[T comparable](g collections.Grapher[T]) (*collections.DiGraph[int], map[int]int) {
+	components, members := TarjanSCC(g)
+	dag := &collections.DiGraph[int]{}
+	for _, component := range members {
+		dag.AddVertex(component[0])
+	}
+
+	condensed := map[int]int{}
+	for id, component := range components {
+		condensed[id] = component + 1
+	}
+	for _, from := range g.VertexIDs() {
+		for _, to := range g.Successors(from) {
+			if condensed[from] != condensed[to] {
+				dag.AddEdge(condensed[from], condensed[to])
+			}
+		}
+	}
+	return dag, condensed
+}
+
+func membership(components [][]int) map[int]int {
+	result := map[int]int{}
+	for i, component := range components {
+		for _, id := range component {
+			result[id] = i
+		}
+	}
+	return result
+}
+
+// Iterative depth-first search from root following neighbours,
+// skipping (and marking) visited vertices, calling postVisit once a vertex is finished
+func depthFirst(root int, neighbours func(int) []int, visited map[int]bool, postVisit func(int)) {
+	if visited[root] {
+		return
+	}
+	visited[root] = true
+	stack := &collections.Stack[*dfsFrame]{}
+	stack.Push(&dfsFrame{root, neighbours(root), 0})
+
+	for !stack.Empty() {
+		frame, _ := stack.Peek()
+		if frame.next == len(frame.successors) {
+			stack.Pop()
+			postVisit(frame.id)
+			continue
+		}
+		next := frame.successors[frame.next]
+		frame.next++
+		if !visited[next] {
+			visited[next] = true
+			stack.Push(&dfsFrame{next, neighbours(next), 0})
+		}
+	}
+}
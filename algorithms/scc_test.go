@@ -0,0 +1,82 @@
+package graphs
+
+import (
+	"math/rand"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+func randomDiGraph(n int, p float64, seed int64) *collections.DiGraph[int] {
+	random := rand.New(rand.NewSource(seed))
+	g := &collections.DiGraph[int]{}
+	for i := 1; i <= n; i++ {
+		g.AddVertex(i)
+	}
+	for from := 1; from <= n; from++ {
+		for to := 1; to <= n; to++ {
+			if from != to && random.Float64() < p {
+				g.AddEdge(from, to)
+			}
+		}
+	}
+	return g
+}
+
+func reachable(g *collections.DiGraph[int], from int) map[int]bool {
+	visited := map[int]bool{}
+	depthFirst(from, g.Successors, visited, func(int) {})
+	return visited
+}
+
+func TestSCC(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g := randomDiGraph(30, 0.05, seed)
+		reach := map[int]map[int]bool{}
+		for _, id := range g.VertexIDs() {
+			reach[id] = reachable(g, id)
+		}
+
+		algorithms := map[string]func(collections.Grapher[int]) (map[int]int, [][]int){
+			"Tarjan":   TarjanSCC[int],
+			"Kosaraju": KosarajuSCC[int],
+		}
+		for name, scc := range algorithms {
+			membership, components := scc(g)
+			for _, a := range g.VertexIDs() {
+				for _, b := range g.VertexIDs() {
+					together := reach[a][b] && reach[b][a]
+					if together != (membership[a] == membership[b]) {
+						t.Fatalf("%v (seed %v): vertices %v and %v wrongly (un)grouped: %v", name, seed, a, b, components)
+					}
+				}
+				for _, b := range g.Successors(a) {
+					if membership[a] > membership[b] {
+						t.Fatalf("%v (seed %v): components are not in topological order: %v", name, seed, components)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCondense(t *testing.T) {
+	g := diGraph(7, [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 4}, {6, 7}, {7, 6}, {6, 5}})
+
+	dag, condensed := Condense[int](g)
+	if dag.VertexCount() != 3 {
+		t.Fatalf("condensation has %v vertices, expected 3", dag.VertexCount())
+	}
+	if condensed[1] != condensed[3] || condensed[4] != condensed[5] || condensed[1] == condensed[4] {
+		t.Fatalf("wrong condensation membership: %v", condensed)
+	}
+	if FindCycle[int](dag) != nil {
+		t.Fatalf("condensation is cyclic")
+	}
+	if successors := dag.Successors(condensed[6]); !slices.Equal(successors, []int{condensed[4]}) {
+		t.Fatalf("condensed successors of {6, 7} = %v, expected %v", successors, []int{condensed[4]})
+	}
+	if value := dag.Vertices[condensed[7]].Value; value != 6 {
+		t.Fatalf("condensed vertex value = %v, expected 6", value)
+	}
+}
@@ -0,0 +1,59 @@
+package graphs
+
+import (
+	"cmp"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Prim's minimum spanning forest with the priority queue made by newHeap, nil
+// for the default binary heap. Every component is spanned from its smallest ID.
+// Edges are given as their two vertex IDs, smaller ID first, and sorted.
+// Returns the edges and their total weight. Self loops are ignored.
+// O((|E|+|V|)*log(|V|)) with a binary heap, O(|E|+|V|*log(|V|)) with a Fibonacci heap.
+func Prim[T comparable](g *collections.WGraph[T], newHeap HeapFactory) ([][2]int, float64) {
+	dense := denseOfWeighted(g)
+	n := len(dense.ids)
+	key := make([]float64, n)
+	parent := make([]int, n)
+	for v := range key {
+		key[v], parent[v] = math.Inf(1), -1
+	}
+	done := make([]bool, n)
+	handles := make([]*collections.PriorityHandle[int, SearchKey], n)
+	edges, total := [][2]int{}, 0.0
+
+	for root := range dense.ids {
+		if done[root] {
+			continue
+		}
+		queue := newHeap.newHeap()
+		key[root] = 0
+		queue.Push(root, SearchKey{0, root})
+		for !queue.Empty() {
+			u, _, _ := queue.Pop()
+			done[u] = true
+			if parent[u] != -1 {
+				edge := [2]int{dense.ids[parent[u]], dense.ids[u]}
+				slices.Sort(edge[:])
+				edges = append(edges, edge)
+				total += key[u]
+			}
+			for k, v := range dense.adjacency[u] {
+				if w := dense.weights[u][k]; !done[v] && w < key[v] {
+					key[v], parent[v] = w, u
+					if handles[v] == nil {
+						handles[v] = queue.Push(v, SearchKey{w, v})
+					} else {
+						queue.DecreaseKey(handles[v], SearchKey{w, v})
+					}
+				}
+			}
+		}
+	}
+	slices.SortFunc(edges, func(a, b [2]int) int {
+		return cmp.Or(cmp.Compare(a[0], b[0]), cmp.Compare(a[1], b[1]))
+	})
+	return edges, total
+}
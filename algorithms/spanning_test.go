@@ -0,0 +1,122 @@
+package graphs
+
+import (
+	"cmp"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+var heapFactories = []struct {
+	name    string
+	newHeap HeapFactory
+}{
+	{"Binary", nil},
+	{"4-ary", func(compare func(a, b SearchKey) int) collections.PriorityHeap[int, SearchKey] {
+		return collections.NewDaryHeap[int](4, compare)
+	}},
+	{"Pairing", func(compare func(a, b SearchKey) int) collections.PriorityHeap[int, SearchKey] {
+		return collections.NewPairingHeap[int](compare)
+	}},
+	{"Fibonacci", func(compare func(a, b SearchKey) int) collections.PriorityHeap[int, SearchKey] {
+		return collections.NewFibonacciHeap[int](compare)
+	}},
+	{"MinMax", func(compare func(a, b SearchKey) int) collections.PriorityHeap[int, SearchKey] {
+		return collections.NewMinMaxHeap[int](compare)
+	}},
+}
+
+// Kruskal's minimum spanning forest weight with a naive union-find
+func kruskalWeight(g *collections.WGraph[int]) (float64, int) {
+	type edge struct {
+		a, b   int
+		weight float64
+	}
+	edges := []edge{}
+	component := map[int]int{}
+	for _, id := range g.VertexIDs() {
+		component[id] = id
+		for _, successor := range g.Successors(id) {
+			if id < successor {
+				weight, _ := g.Weight(id, successor)
+				edges = append(edges, edge{id, successor, weight})
+			}
+		}
+	}
+	slices.SortFunc(edges, func(a, b edge) int {
+		return cmp.Compare(a.weight, b.weight)
+	})
+	total, count := 0.0, 0
+	for _, e := range edges {
+		from, to := component[e.a], component[e.b]
+		if from == to {
+			continue
+		}
+		for id, c := range component {
+			if c == from {
+				component[id] = to
+			}
+		}
+		total += e.weight
+		count++
+	}
+	return total, count
+}
+
+func TestPrim(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		base, _ := ErdosRenyi(40, 0.08, seed)
+		g := Weighted(base, IntegerWeights(-5, 20), seed)
+		g.AddEdge(1, 1, -100)
+		expected, count := kruskalWeight(g)
+		for _, factory := range heapFactories {
+			edges, total := Prim(g, factory.newHeap)
+			if len(edges) != count || math.Abs(total-expected) > 1e-9 {
+				t.Fatalf("%v heap, seed %v: %v edges weighing %v, expected %v weighing %v", factory.name, seed, len(edges), total, count, expected)
+			}
+			sum, components := 0.0, map[int]int{}
+			for _, id := range g.VertexIDs() {
+				components[id] = id
+			}
+			var find func(id int) int
+			find = func(id int) int {
+				if components[id] != id {
+					components[id] = find(components[id])
+				}
+				return components[id]
+			}
+			for i, edge := range edges {
+				weight, err := g.Weight(edge[0], edge[1])
+				if err != nil || edge[0] >= edge[1] || i > 0 && cmp.Compare(edges[i-1][0], edge[0]) > 0 {
+					t.Fatalf("%v heap: edge %v out of order or missing", factory.name, edge)
+				}
+				if find(edge[0]) == find(edge[1]) {
+					t.Fatalf("%v heap: edge %v closes a cycle", factory.name, edge)
+				}
+				components[find(edge[0])] = find(edge[1])
+				sum += weight
+			}
+			if math.Abs(sum-total) > 1e-9 {
+				t.Fatalf("%v heap: total %v, edges weigh %v", factory.name, total, sum)
+			}
+		}
+	}
+
+	edges, total := Prim(&collections.WGraph[int]{}, nil)
+	if len(edges) != 0 || total != 0 {
+		t.Fatalf("spanning tree %v of an empty graph", edges)
+	}
+}
+
+func BenchmarkPrim(b *testing.B) {
+	base, _ := ErdosRenyi(2000, 0.01, 0)
+	g := Weighted(base, UniformWeights(0, 1), 0)
+	for _, factory := range heapFactories {
+		b.Run(factory.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Prim(g, factory.newHeap)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package graphs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+var (
+	ErrUndirected = errors.New("Graph is undirected")
+	ErrDirected   = errors.New("Graph is directed")
+)
+
+// Returned when a topological order is requested for a cyclic graph
+type CycleError struct {
+	// Vertex IDs along the cycle, the last vertex leads back to the first
+	Cycle []int
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("Graph contains a cycle: %v", e.Cycle)
+}
+
+// Kahn's algorithm.
+// Whenever several vertices are ready, the one with the smallest ID comes first,
+// so the order is stable for a given graph.
+func TopologicalSort[T comparable](g collections.Grapher[T]) ([]int, error) {
+	if !g.Directed() {
+		return nil, ErrUndirected
+	}
+	inDegree := inDegrees(g)
+	ready := collections.NewPriorityQueue(cmp.Compare[int])
+	for _, id := range g.VertexIDs() {
+		if inDegree[id] == 0 {
+			ready.Push(id)
+		}
+	}
+
+	order := make([]int, 0, g.VertexCount())
+	for !ready.Empty() {
+		id, _ := ready.Pop()
+		order = append(order, id)
+		for _, successor := range g.Successors(id) {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				ready.Push(successor)
+			}
+		}
+	}
+
+	if len(order) < g.VertexCount() {
+		return nil, &CycleError{FindCycle(g)}
+	}
+	return order, nil
+}
+
+// Depth-first topological sort (reverse postorder).
+// Vertices and successors are visited in ascending ID order.
+func TopologicalSortDFS[T comparable](g collections.Grapher[T]) ([]int, error) {
+	if !g.Directed() {
+		return nil, ErrUndirected
+	}
+	postorder := make([]int, 0, g.VertexCount())
+	cycle := dfs(g, func(id int) {
+		postorder = append(postorder, id)
+	})
+	if cycle != nil {
+		return nil, &CycleError{cycle}
+	}
+	slices.Reverse(postorder)
+	return postorder, nil
+}
+
+// Groups the vertices into layers, so that every edge leads from an earlier
+// layer to a later one. The vertices of a single layer do not depend on each
+// other and may be processed in parallel. Each layer is sorted by ID.
+func TopologicalLayers[T comparable](g collections.Grapher[T]) ([][]int, error) {
+	if !g.Directed() {
+		return nil, ErrUndirected
+	}
+	inDegree := inDegrees(g)
+	layer := []int{}
+	for _, id := range g.VertexIDs() {
+		if inDegree[id] == 0 {
+			layer = append(layer, id)
+		}
+	}
+
+	layers := [][]int{}
+	visited := 0
+	for len(layer) > 0 {
+		layers = append(layers, layer)
+		visited += len(layer)
+		next := []int{}
+		for _, id := range layer {
+			for _, successor := range g.Successors(id) {
+				inDegree[successor]--
+				if inDegree[successor] == 0 {
+					next = append(next, successor)
+				}
+			}
+		}
+		slices.Sort(next)
+		layer = next
+	}
+
+	if visited < g.VertexCount() {
+		return nil, &CycleError{FindCycle(g)}
+	}
+	return layers, nil
+}
+
+// Returns the vertex IDs of a directed cycle in g, or nil if g is acyclic.
+// The last vertex of the result leads back to the first.
+func FindCycle[T comparable](g collections.Grapher[T]) []int {
+	return dfs(g, func(int) {})
+}
+
+func inDegrees[T comparable](g collections.Grapher[T]) map[int]int {
+	inDegree := map[int]int{}
+	for _, id := range g.VertexIDs() {
+		inDegree[id] = len(g.Predecessors(id))
+	}
+	return inDegree
+}
+
+type dfsFrame struct {
+	id         int
+	successors []int
+	next       int
+}
+
+const (
+	unvisited = iota
+	onPath
+	finished
+)
+
+// Iterative depth-first search over the whole graph, calling postVisit once
+// a vertex and all of its descendants are finished.
+// Stops and returns the cycle as soon as a back edge is found.
+func dfs[T comparable](g collections.Grapher[T], postVisit func(int)) []int {
+	state := map[int]int{}
+	stack := &collections.Stack[*dfsFrame]{}
+
+	for _, root := range g.VertexIDs() {
+		if state[root] != unvisited {
+			continue
+		}
+		state[root] = onPath
+		stack.Push(&dfsFrame{root, g.Successors(root), 0})
+
+		for !stack.Empty() {
+			frame, _ := stack.Peek()
+			if frame.next == len(frame.successors) {
+				stack.Pop()
+				state[frame.id] = finished
+				postVisit(frame.id)
+				continue
+			}
+			successor := frame.successors[frame.next]
+			frame.next++
+
+			switch state[successor] {
+			case onPath:
+				return cycleFromStack(*stack, successor)
+			case unvisited:
+				state[successor] = onPath
+				stack.Push(&dfsFrame{successor, g.Successors(successor), 0})
+			}
+		}
+	}
+	return nil
+}
+
+// The frames above (and including) start's frame form the cycle
+func cycleFromStack(stack []*dfsFrame, start int) []int {
+	cycle := []int{}
+	for i := len(stack) - 1; i >= 0; i-- {
+		cycle = append(cycle, stack[i].id)
+		if stack[i].id == start {
+			break
+		}
+	}
+	slices.Reverse(cycle)
+	return cycle
+}
@@ -0,0 +1,105 @@
+package graphs
+
+import (
+	"errors"
+	"mayerus/csgo/collections"
+	"slices"
+	"testing"
+)
+
+// Builds a directed graph with vertices 1..n and the given edges
+func diGraph(n int, edges [][2]int) *collections.DiGraph[int] {
+	g := &collections.DiGraph[int]{}
+	for i := 1; i <= n; i++ {
+		g.AddVertex(i)
+	}
+	for _, edge := range edges {
+		g.AddEdge(edge[0], edge[1])
+	}
+	return g
+}
+
+func checkTopologicalOrder(g *collections.DiGraph[int], order []int, t *testing.T) {
+	if len(order) != g.VertexCount() {
+		t.Fatalf("order %v does not cover all %v vertices", order, g.VertexCount())
+	}
+	position := map[int]int{}
+	for i, id := range order {
+		position[id] = i
+	}
+	for _, from := range g.VertexIDs() {
+		for _, to := range g.Successors(from) {
+			if position[from] >= position[to] {
+				t.Fatalf("edge %v -> %v violates order %v", from, to, order)
+			}
+		}
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := diGraph(6, [][2]int{{6, 3}, {6, 1}, {5, 1}, {5, 2}, {3, 4}, {4, 2}})
+
+	order, err := TopologicalSort[int](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTopologicalOrder(g, order, t)
+	if expected := []int{5, 6, 1, 3, 4, 2}; !slices.Equal(order, expected) {
+		t.Fatalf("Kahn order = %v, expected %v", order, expected)
+	}
+
+	order, err = TopologicalSortDFS[int](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTopologicalOrder(g, order, t)
+	if expected := []int{6, 5, 3, 4, 2, 1}; !slices.Equal(order, expected) {
+		t.Fatalf("DFS order = %v, expected %v", order, expected)
+	}
+}
+
+func TestTopologicalLayers(t *testing.T) {
+	g := diGraph(6, [][2]int{{1, 3}, {2, 3}, {3, 4}, {3, 5}, {2, 6}})
+
+	layers, err := TopologicalLayers[int](g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]int{{1, 2}, {3, 6}, {4, 5}}
+	if !slices.EqualFunc(layers, expected, slices.Equal[[]int]) {
+		t.Fatalf("layers = %v, expected %v", layers, expected)
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	g := diGraph(5, [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 2}, {4, 5}})
+
+	sorts := map[string]func(collections.Grapher[int]) ([]int, error){
+		"Kahn": TopologicalSort[int],
+		"DFS":  TopologicalSortDFS[int],
+		"Layers": func(g collections.Grapher[int]) ([]int, error) {
+			_, err := TopologicalLayers(g)
+			return nil, err
+		},
+	}
+	for name, sort := range sorts {
+		_, err := sort(g)
+		var cycleErr *CycleError
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("%v: expected a CycleError, got %v", name, err)
+		}
+		if !slices.Equal(cycleErr.Cycle, []int{2, 3, 4}) {
+			t.Fatalf("%v: cycle = %v, expected [2 3 4]", name, cycleErr.Cycle)
+		}
+	}
+
+	if cycle := FindCycle[int](diGraph(3, [][2]int{{1, 2}, {2, 3}, {1, 3}})); cycle != nil {
+		t.Fatalf("found cycle %v in an acyclic graph", cycle)
+	}
+	if cycle := FindCycle[int](diGraph(1, [][2]int{{1, 1}})); !slices.Equal(cycle, []int{1}) {
+		t.Fatalf("self loop cycle = %v, expected [1]", cycle)
+	}
+	if _, err := TopologicalSort[int](&collections.Graph[int]{}); err != ErrUndirected {
+		t.Fatalf("expected ErrUndirected, got %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package graphs
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"mayerus/csgo/collections"
+	"slices"
+)
+
+// Tours are closed walks given as vertex IDs starting at the smallest ID,
+// with the first vertex repeated at the end.
+
+// Largest number of vertices the Held–Karp solvers accept, they need O(2^n*n) memory
+const HeldKarpLimit = 18
+
+var (
+	ErrNoTour            = errors.New("Graph has no Hamiltonian cycle")
+	ErrNoHamiltonianPath = errors.New("Graph has no Hamiltonian path")
+	ErrTooManyVertices   = errors.New("Too many vertices for an exact solution")
+)
+
+// Held–Karp dynamic programming: the shortest cycle visiting every vertex once,
+// O(2^n*n^2). Directed graphs are toured along their edges.
+// A graph with a single vertex has the tour [id, id] of length 0.
+func TravellingSalesman[T comparable](g collections.WeightedGrapher[T]) ([]int, float64, error) {
+	dense := denseOfWeighted(g)
+	n := len(dense.ids)
+	switch {
+	case n > HeldKarpLimit:
+		return nil, 0, ErrTooManyVertices
+	case n == 0:
+		return []int{}, 0, nil
+	case n == 1:
+		return []int{dense.ids[0], dense.ids[0]}, 0, nil
+	}
+
+	// the tour starts at vertex 0, the subsets range over the others
+	w := dense.weightMatrix()
+	others := make([][]float64, n-1)
+	first := make([]float64, n-1)
+	for i := range others {
+		others[i] = w[i+1][1:]
+		first[i] = w[0][i+1]
+	}
+	solution := heldKarp(others, first)
+	last, length := -1, math.Inf(1)
+	for v := range others {
+		if total := solution.length(v) + w[v+1][0]; total < length {
+			last, length = v, total
+		}
+	}
+	if last == -1 {
+		return nil, 0, ErrNoTour
+	}
+
+	tour := []int{dense.ids[0]}
+	for _, v := range solution.path(last) {
+		tour = append(tour, dense.ids[v+1])
+	}
+	return append(tour, dense.ids[0]), length, nil
+}
+
+// Held–Karp dynamic programming: the shortest path visiting every vertex once,
+// between any two vertices, O(2^n*n^2)
+func HamiltonianPath[T comparable](g collections.WeightedGrapher[T]) ([]int, float64, error) {
+	dense := denseOfWeighted(g)
+	n := len(dense.ids)
+	if n > HeldKarpLimit {
+		return nil, 0, ErrTooManyVertices
+	}
+	if n == 0 {
+		return []int{}, 0, nil
+	}
+
+	solution := heldKarp(dense.weightMatrix(), make([]float64, n))
+	last, length := -1, math.Inf(1)
+	for v := 0; v < n; v++ {
+		if solution.length(v) < length {
+			last, length = v, solution.length(v)
+		}
+	}
+	if last == -1 {
+		return nil, 0, ErrNoHamiltonianPath
+	}
+	path := solution.path(last)
+	for i, v := range path {
+		path[i] = dense.ids[v]
+	}
+	return path, length, nil
+}
+
+// Christofides heuristic followed by 2-opt improvement, for graphs too large
+// for TravellingSalesman. Distances are shortest path lengths, so the graph
+// only needs to be connected: consecutive tour vertices that are not adjacent
+// stand for a shortest path between them.
+// The matching of odd degree vertices is greedy rather than minimum weight,
+// so the 3/2 approximation bound of Christofides is not guaranteed.
+func ApproximateTour[T comparable](g *collections.WGraph[T]) ([]int, float64, error) {
+	dense := denseOfWeighted(g)
+	distance, err := dense.distanceMatrix()
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(dense.ids)
+	if n == 0 {
+		return []int{}, 0, nil
+	}
+
+	euler := &eulerGraph{incident: make([][]int, n)}
+	degree := make([]int, n)
+	for _, edge := range primTree(distance) {
+		euler.add(edge[0], edge[1])
+		degree[edge[0]]++
+		degree[edge[1]]++
+	}
+	odd := []int{}
+	for v, d := range degree {
+		if d%2 != 0 {
+			odd = append(odd, v)
+		}
+	}
+	for _, edge := range greedyMatching(odd, distance) {
+		euler.add(edge[0], edge[1])
+	}
+
+	// shortcut the Eulerian circuit past repeated vertices
+	tour := make([]int, 0, n+1)
+	visited := make([]bool, n)
+	for _, v := range euler.walk(0) {
+		if !visited[v] {
+			visited[v] = true
+			tour = append(tour, v)
+		}
+	}
+	twoOpt(tour, distance)
+	result, length := tourResult(tour, dense.ids, distance)
+	return result, length, nil
+}
+
+// 2-opt local search: reverses tour segments as long as that shortens the tour.
+// The tour must visit every vertex of g once, as returned by the other solvers,
+// distances are shortest path lengths as for ApproximateTour.
+func TwoOpt[T comparable](g *collections.WGraph[T], tour []int) ([]int, float64, error) {
+	dense := denseOfWeighted(g)
+	distance, err := dense.distanceMatrix()
+	if err != nil {
+		return nil, 0, err
+	}
+	n := len(dense.ids)
+	if n == 0 {
+		return []int{}, 0, nil
+	}
+	if len(tour) != n+1 || tour[0] != tour[n] {
+		return nil, 0, ErrNoTour
+	}
+
+	// every vertex once, rotated to start at vertex 0
+	order := make([]int, n)
+	seen := make([]bool, n)
+	start := 0
+	for i, id := range tour[:n] {
+		v, found := dense.index[id]
+		if !found {
+			return nil, 0, &collections.UnknownVertexError{ID: id}
+		}
+		if seen[v] {
+			return nil, 0, ErrNoTour
+		}
+		seen[v] = true
+		order[i] = v
+		if v == 0 {
+			start = i
+		}
+	}
+	order = append(order[start:], order[:start]...)
+	twoOpt(order, distance)
+	result, length := tourResult(order, dense.ids, distance)
+	return result, length, nil
+}
+
+// Edge weights as a matrix, +Inf where there is no edge
+func (g *denseGraph) weightMatrix() [][]float64 {
+	n := len(g.ids)
+	w := make([][]float64, n)
+	for u := range w {
+		w[u] = make([]float64, n)
+		for v := range w[u] {
+			w[u][v] = math.Inf(1)
+		}
+		for k, v := range g.adjacency[u] {
+			w[u][v] = g.weights[u][k]
+		}
+	}
+	return w
+}
+
+// Shortest path lengths between all pairs, ErrNoTour if the graph is disconnected
+func (g *denseGraph) distanceMatrix() ([][]float64, error) {
+	distance := make([][]float64, len(g.ids))
+	for source := range distance {
+		paths, err := g.dijkstraPaths(source)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths.order) != len(g.ids) {
+			return nil, ErrNoTour
+		}
+		distance[source] = paths.distance
+	}
+	return distance, nil
+}
+
+// Held–Karp table over the subsets of n items: lengths[mask*n+v] is the length of the
+// shortest path visiting exactly the items in mask and ending at v
+type heldKarpTable struct {
+	n       int
+	lengths []float64
+	parent  []int8
+}
+
+// Fills the table for paths starting at any item v at a cost of first[v]
+func heldKarp(w [][]float64, first []float64) *heldKarpTable {
+	n := len(w)
+	table := &heldKarpTable{n: n, lengths: make([]float64, n<<n), parent: make([]int8, n<<n)}
+	for i := range table.lengths {
+		table.lengths[i] = math.Inf(1)
+	}
+	for v := 0; v < n; v++ {
+		table.lengths[(1<<v)*n+v] = first[v]
+		table.parent[(1<<v)*n+v] = -1
+	}
+	for mask := 1; mask < 1<<n; mask++ {
+		for v := 0; v < n; v++ {
+			current := table.lengths[mask*n+v]
+			if mask&(1<<v) == 0 || math.IsInf(current, 1) {
+				continue
+			}
+			for next := 0; next < n; next++ {
+				if mask&(1<<next) != 0 {
+					continue
+				}
+				extended := (mask|1<<next)*n + next
+				if candidate := current + w[v][next]; candidate < table.lengths[extended] {
+					table.lengths[extended] = candidate
+					table.parent[extended] = int8(v)
+				}
+			}
+		}
+	}
+	return table
+}
+
+// Length of the shortest path through all items ending at v
+func (t *heldKarpTable) length(v int) float64 {
+	return t.lengths[((1<<t.n)-1)*t.n+v]
+}
+
+// Items of the shortest path through all items ending at v
+func (t *heldKarpTable) path(v int) []int {
+	path := make([]int, t.n)
+	mask := (1 << t.n) - 1
+	for i := t.n - 1; i >= 0; i-- {
+		path[i] = v
+		previous := int(t.parent[mask*t.n+v])
+		mask &^= 1 << v
+		v = previous
+	}
+	return path
+}
+
+// Prim's minimum spanning tree over a complete distance matrix, O(n^2)
+func primTree(distance [][]float64) [][2]int {
+	n := len(distance)
+	tree := make([][2]int, 0, n)
+	inTree := make([]bool, n)
+	closest := make([]int, n)
+	best := make([]float64, n)
+	for v := range best {
+		best[v] = math.Inf(1)
+	}
+	best[0] = 0
+	for range distance {
+		u := -1
+		for v := range best {
+			if !inTree[v] && (u == -1 || best[v] < best[u]) {
+				u = v
+			}
+		}
+		inTree[u] = true
+		if u != 0 {
+			tree = append(tree, [2]int{closest[u], u})
+		}
+		for v := range best {
+			if !inTree[v] && distance[u][v] < best[v] {
+				best[v], closest[v] = distance[u][v], u
+			}
+		}
+	}
+	return tree
+}
+
+// Perfect matching of an even number of vertices, repeatedly pairing the
+// closest two that are still unmatched
+func greedyMatching(vertices []int, distance [][]float64) [][2]int {
+	pairs := [][2]int{}
+	for i, a := range vertices {
+		for _, b := range vertices[i+1:] {
+			pairs = append(pairs, [2]int{a, b})
+		}
+	}
+	slices.SortStableFunc(pairs, func(p, q [2]int) int {
+		return cmp.Compare(distance[p[0]][p[1]], distance[q[0]][q[1]])
+	})
+	matched := map[int]bool{}
+	matching := [][2]int{}
+	for _, pair := range pairs {
+		if !matched[pair[0]] && !matched[pair[1]] {
+			matched[pair[0]], matched[pair[1]] = true, true
+			matching = append(matching, pair)
+		}
+	}
+	return matching
+}
+
+// Improves the open tour in place until no segment reversal shortens it.
+// The first vertex stays in place.
+func twoOpt(tour []int, distance [][]float64) {
+	n := len(tour)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					// both edges meet at the first vertex
+					continue
+				}
+				a, b, c, d := tour[i], tour[i+1], tour[j], tour[(j+1)%n]
+				if distance[a][c]+distance[b][d] < distance[a][b]+distance[c][d]-pathEpsilon {
+					slices.Reverse(tour[i+1 : j+1])
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+// Closes the tour and maps it to vertex IDs
+func tourResult(tour []int, ids []int, distance [][]float64) ([]int, float64) {
+	result := make([]int, len(tour)+1)
+	length := 0.0
+	for i, v := range tour {
+		result[i] = ids[v]
+		length += distance[v][tour[(i+1)%len(tour)]]
+	}
+	result[len(tour)] = ids[tour[0]]
+	return result, length
+}
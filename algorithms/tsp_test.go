@@ -0,0 +1,216 @@
+package graphs
+
+import (
+	"math"
+	"math/rand"
+	"mayerus/csgo/collections"
+	"testing"
+)
+
+// Complete graph over random points in the unit square, weighted by distance
+func euclideanGraph(n int, seed int64) *collections.WGraph[int] {
+	random := rand.New(rand.NewSource(seed))
+	g := &collections.WGraph[int]{}
+	x, y := make([]float64, n+1), make([]float64, n+1)
+	for id := 1; id <= n; id++ {
+		g.AddVertex(id - 1)
+		x[id], y[id] = random.Float64(), random.Float64()
+	}
+	for a := 1; a <= n; a++ {
+		for b := a + 1; b <= n; b++ {
+			g.AddEdge(a, b, math.Hypot(x[a]-x[b], y[a]-y[b]))
+		}
+	}
+	return g
+}
+
+// Shortest Hamiltonian path or cycle by trying every order of the vertices 1..n
+func bruteForceHamiltonian(g collections.WeightedGrapher[int], n int, cycle bool) float64 {
+	best := math.Inf(1)
+	order := make([]int, 0, n)
+	used := make([]bool, n+1)
+	var extend func(length float64)
+	extend = func(length float64) {
+		if len(order) == n {
+			if cycle {
+				weight, err := g.Weight(order[n-1], order[0])
+				if err != nil {
+					return
+				}
+				length += weight
+			}
+			best = min(best, length)
+			return
+		}
+		for id := 1; id <= n; id++ {
+			if used[id] || (cycle && len(order) == 0 && id != 1) {
+				continue
+			}
+			step := 0.0
+			if len(order) > 0 {
+				weight, err := g.Weight(order[len(order)-1], id)
+				if err != nil {
+					continue
+				}
+				step = weight
+			}
+			used[id] = true
+			order = append(order, id)
+			extend(length + step)
+			order = order[:len(order)-1]
+			used[id] = false
+		}
+	}
+	extend(0)
+	return best
+}
+
+// Checks that the walk visits every vertex once and has the given length
+func checkTour(name string, g collections.WeightedGrapher[int], walk []int, length float64, closed bool, t *testing.T) {
+	n := g.VertexCount()
+	if closed {
+		if len(walk) != n+1 || walk[0] != walk[n] {
+			t.Fatalf("%v: %v is not a closed tour", name, walk)
+		}
+		walk = walk[:n]
+	}
+	seen := map[int]bool{}
+	total := 0.0
+	for i, id := range walk {
+		if seen[id] || !g.HasVertex(id) {
+			t.Fatalf("%v: %v visits %v twice", name, walk, id)
+		}
+		seen[id] = true
+		next := i + 1
+		if next == len(walk) {
+			if !closed {
+				break
+			}
+			next = 0
+		}
+		weight, err := g.Weight(id, walk[next])
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		total += weight
+	}
+	if len(seen) != n || !closeTo(total, length) {
+		t.Fatalf("%v: %v of length %v, reported %v", name, walk, total, length)
+	}
+}
+
+func TestTravellingSalesman(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		g := Weighted(Complete(7), IntegerWeights(1, 20), seed)
+		tour, length, err := TravellingSalesman[int](g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTour("complete", g, tour, length, true, t)
+		if expected := bruteForceHamiltonian(g, 7, true); !closeTo(length, expected) {
+			t.Fatalf("seed %v: tour %v of length %v, optimum %v", seed, tour, length, expected)
+		}
+
+		sparse, _ := ErdosRenyi(8, 0.5, seed)
+		weighted := Weighted(sparse, IntegerWeights(1, 9), seed)
+		expected := bruteForceHamiltonian(weighted, 8, true)
+		tour, length, err = TravellingSalesman[int](weighted)
+		if math.IsInf(expected, 1) {
+			if err != ErrNoTour {
+				t.Fatalf("seed %v: expected ErrNoTour, got %v %v", seed, tour, err)
+			}
+			continue
+		}
+		checkTour("sparse", weighted, tour, length, true, t)
+		if !closeTo(length, expected) {
+			t.Fatalf("seed %v: tour %v of length %v, optimum %v", seed, tour, length, expected)
+		}
+	}
+
+	directed := &collections.WDiGraph[int]{}
+	for i := 0; i < 4; i++ {
+		directed.AddVertex(i)
+	}
+	directed.AddEdge(1, 3, 1)
+	directed.AddEdge(3, 2, 1)
+	directed.AddEdge(2, 4, 1)
+	directed.AddEdge(4, 1, 1)
+	directed.AddEdge(1, 2, 1)
+	tour, _, err := TravellingSalesman[int](directed)
+	if err != nil || len(tour) != 5 || tour[1] != 3 {
+		t.Fatalf("directed tour %v, %v", tour, err)
+	}
+
+	if _, _, err := TravellingSalesman[int](euclideanGraph(HeldKarpLimit+1, 0)); err != ErrTooManyVertices {
+		t.Fatalf("expected ErrTooManyVertices, got %v", err)
+	}
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		sparse, _ := ErdosRenyi(8, 0.4, seed)
+		g := Weighted(sparse, IntegerWeights(1, 9), seed)
+		expected := bruteForceHamiltonian(g, 8, false)
+		path, length, err := HamiltonianPath[int](g)
+		if math.IsInf(expected, 1) {
+			if err != ErrNoHamiltonianPath {
+				t.Fatalf("seed %v: expected ErrNoHamiltonianPath, got %v %v", seed, path, err)
+			}
+			continue
+		}
+		checkTour("sparse", g, path, length, false, t)
+		if !closeTo(length, expected) {
+			t.Fatalf("seed %v: path %v of length %v, optimum %v", seed, path, length, expected)
+		}
+	}
+}
+
+func TestApproximateTour(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g := euclideanGraph(12, seed)
+		tour, length, err := ApproximateTour(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTour("approximate", g, tour, length, true, t)
+		_, optimum, _ := TravellingSalesman[int](g)
+		if length > 1.5*optimum {
+			t.Fatalf("seed %v: tour of length %v, optimum %v", seed, length, optimum)
+		}
+	}
+
+	g := euclideanGraph(200, 1)
+	identity := make([]int, 201)
+	for i := range identity {
+		identity[i] = i%200 + 1
+	}
+	identityLength := 0.0
+	for i := 1; i < len(identity); i++ {
+		weight, _ := g.Weight(identity[i-1], identity[i])
+		identityLength += weight
+	}
+	tour, length, err := TwoOpt(g, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTour("2-opt", g, tour, length, true, t)
+	if length >= identityLength {
+		t.Fatalf("2-opt did not shorten the tour: %v, was %v", length, identityLength)
+	}
+
+	if _, _, err := ApproximateTour(Weighted(Path(1), ConstantWeights(1), 0)); err != nil {
+		t.Fatal(err)
+	}
+	disconnected := Weighted(Path(3), ConstantWeights(1), 0)
+	disconnected.AddVertex(3)
+	if _, _, err := ApproximateTour(disconnected); err != ErrNoTour {
+		t.Fatalf("expected ErrNoTour, got %v", err)
+	}
+}
+
+func BenchmarkApproximateTour(b *testing.B) {
+	g := euclideanGraph(300, 0)
+	for i := 0; i < b.N; i++ {
+		ApproximateTour(g)
+	}
+}
@@ -0,0 +1,227 @@
+package collections
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrQueueClosed = errors.New("Queue is closed")
+	ErrQueueFull   = errors.New("Queue is full")
+	ErrQueueEmpty  = errors.New("Queue is empty")
+)
+
+// Bounded FIFO queue safe for concurrent use.
+// Blocked producers and consumers are served in the order they arrived:
+// an item pushed while consumers wait goes straight to the one waiting longest,
+// and space freed by a pop goes to the producer waiting longest.
+// After Close the remaining items can still be popped.
+type BlockingQueue[T comparable] struct {
+	mutex     sync.Mutex
+	items     Queue[T]
+	capacity  int
+	closed    bool
+	consumers Queue[*queueWaiter[T]]
+	producers Queue[*queueWaiter[T]]
+}
+
+var _ Queuer[int] = &BlockingQueue[int]{}
+
+// States of a queueWaiter
+const (
+	waiterPending = iota
+	waiterServed
+	waiterRejected
+	waiterCancelled
+)
+
+// Blocked caller, its state is guarded by the queue's mutex
+type queueWaiter[T comparable] struct {
+	item  T
+	state int
+	// Closed once the state leaves waiterPending
+	ready chan struct{}
+}
+
+// Queue holding at most capacity items, unbounded if capacity is 0
+func NewBlockingQueue[T comparable](capacity int) *BlockingQueue[T] {
+	if capacity < 0 {
+		panic("BlockingQueue capacity is negative")
+	}
+	return &BlockingQueue[T]{capacity: capacity}
+}
+
+// Waits for space, panics if the queue is closed like a send on a closed channel
+func (q *BlockingQueue[T]) Push(item T) {
+	if err := q.PushCtx(context.Background(), item); err != nil {
+		panic(err)
+	}
+}
+
+// Waits for an item, ErrQueueClosed once the queue is closed and drained
+func (q *BlockingQueue[T]) Pop() (T, error) {
+	return q.PopCtx(context.Background())
+}
+
+// Waits for space until the context is done.
+// Returns ErrQueueClosed if the queue is or gets closed first.
+func (q *BlockingQueue[T]) PushCtx(ctx context.Context, item T) error {
+	q.mutex.Lock()
+	if err := q.push(item); err != ErrQueueFull {
+		q.mutex.Unlock()
+		return err
+	}
+	waiter := &queueWaiter[T]{item: item, ready: make(chan struct{})}
+	q.producers.Push(waiter)
+	q.mutex.Unlock()
+
+	state := q.wait(ctx, waiter)
+	switch state {
+	case waiterServed:
+		return nil
+	case waiterRejected:
+		return ErrQueueClosed
+	}
+	return ctx.Err()
+}
+
+// Waits for an item until the context is done.
+// Returns ErrQueueClosed if the queue is or gets closed and drained first.
+func (q *BlockingQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	q.mutex.Lock()
+	if item, err := q.pop(); err != ErrQueueEmpty {
+		q.mutex.Unlock()
+		return item, err
+	}
+	waiter := &queueWaiter[T]{ready: make(chan struct{})}
+	q.consumers.Push(waiter)
+	q.mutex.Unlock()
+
+	state := q.wait(ctx, waiter)
+	switch state {
+	case waiterServed:
+		return waiter.item, nil
+	case waiterRejected:
+		return waiter.item, ErrQueueClosed
+	}
+	var zero T
+	return zero, ctx.Err()
+}
+
+// Pushes without waiting, ErrQueueFull if there is no space
+func (q *BlockingQueue[T]) TryPush(item T) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.push(item)
+}
+
+// Pops without waiting, ErrQueueEmpty if there are no items
+// or ErrQueueClosed if the queue is closed and drained
+func (q *BlockingQueue[T]) TryPop() (T, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.pop()
+}
+
+// Rejects further pushes and wakes all waiting callers with ErrQueueClosed.
+// Queued items stay available to Pop. Closing twice has no effect.
+func (q *BlockingQueue[T]) Close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.closed = true
+	for _, waiters := range []*Queue[*queueWaiter[T]]{&q.producers, &q.consumers} {
+		for nextWaiter(waiters) != nil {
+			waiter, _ := waiters.Pop()
+			waiter.finish(waiterRejected)
+		}
+	}
+}
+
+func (q *BlockingQueue[T]) Closed() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.closed
+}
+
+func (q *BlockingQueue[T]) Count() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.items.Count()
+}
+
+// Largest number of queued items, 0 if unbounded
+func (q *BlockingQueue[T]) Cap() int {
+	return q.capacity
+}
+
+// Hands the item to the longest waiting consumer or queues it if there is space.
+// Producers already waiting keep their turn. Must hold the mutex.
+func (q *BlockingQueue[T]) push(item T) error {
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if consumer := nextWaiter(&q.consumers); consumer != nil {
+		q.consumers.Pop()
+		consumer.item = item
+		consumer.finish(waiterServed)
+		return nil
+	}
+	if nextWaiter(&q.producers) != nil || q.capacity > 0 && q.items.Count() >= q.capacity {
+		return ErrQueueFull
+	}
+	q.items.Push(item)
+	return nil
+}
+
+// Takes the oldest item and lets the longest waiting producer fill the freed space.
+// Must hold the mutex.
+func (q *BlockingQueue[T]) pop() (T, error) {
+	item, err := q.items.Pop()
+	if err != nil {
+		if q.closed {
+			return item, ErrQueueClosed
+		}
+		return item, ErrQueueEmpty
+	}
+	if producer := nextWaiter(&q.producers); producer != nil {
+		q.producers.Pop()
+		q.items.Push(producer.item)
+		producer.finish(waiterServed)
+	}
+	return item, nil
+}
+
+// Blocks until the waiter is finished or the context is done, and returns
+// the final state. A waiter served just as its context ended counts as served.
+func (q *BlockingQueue[T]) wait(ctx context.Context, waiter *queueWaiter[T]) int {
+	select {
+	case <-waiter.ready:
+	case <-ctx.Done():
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if waiter.state == waiterPending {
+		// left in the waiter queue, skipped once it reaches the front
+		waiter.state = waiterCancelled
+	}
+	return waiter.state
+}
+
+// Longest waiting caller that has not given up, left at the front of the queue.
+// Drops cancelled waiters on the way. Must hold the mutex.
+func nextWaiter[T comparable](waiters *Queue[*queueWaiter[T]]) *queueWaiter[T] {
+	for !waiters.Empty() {
+		waiter, _ := waiters.Peek()
+		if waiter.state == waiterPending {
+			return waiter
+		}
+		waiters.Pop()
+	}
+	return nil
+}
+
+func (w *queueWaiter[T]) finish(state int) {
+	w.state = state
+	close(w.ready)
+}
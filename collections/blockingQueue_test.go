@@ -0,0 +1,216 @@
+package collections
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Waits until the number of waiting callers reaches n
+func awaitWaiters[T comparable](q *BlockingQueue[T], waiters *Queue[*queueWaiter[T]], n int, t *testing.T) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q.mutex.Lock()
+		count := waiters.Count()
+		q.mutex.Unlock()
+		if count == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%v callers waiting, expected %v", count, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBlockingQueueTry(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	if err := q.TryPush(1); err != nil {
+		t.Fatal(err)
+	}
+	q.TryPush(2)
+	if err := q.TryPush(3); err != ErrQueueFull {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+	if q.Count() != 2 || q.Cap() != 2 {
+		t.Fatalf("count %v, capacity %v", q.Count(), q.Cap())
+	}
+	for _, expected := range []int{1, 2} {
+		if item, err := q.TryPop(); item != expected || err != nil {
+			t.Fatalf("popped %v, %v, expected %v", item, err, expected)
+		}
+	}
+	if _, err := q.TryPop(); err != ErrQueueEmpty {
+		t.Fatalf("expected ErrQueueEmpty, got %v", err)
+	}
+
+	unbounded := NewBlockingQueue[int](0)
+	for i := 0; i < 1000; i++ {
+		if err := unbounded.TryPush(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBlockingQueueContext(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PopCtx(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+	// the cancelled consumer must not swallow the next item
+	q.Push(1)
+	if q.Count() != 1 {
+		t.Fatalf("item handed to a cancelled consumer")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- q.PushCtx(ctx, 2)
+	}()
+	awaitWaiters(q, &q.producers, 1, t)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	q.Pop()
+	if q.Count() != 0 {
+		t.Fatalf("cancelled producer's item was queued")
+	}
+}
+
+func TestBlockingQueueClose(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	errs := make(chan error, 2)
+	go func() {
+		_, err := q.Pop()
+		errs <- err
+	}()
+	awaitWaiters(q, &q.consumers, 1, t)
+	q.Close()
+	if err := <-errs; err != ErrQueueClosed {
+		t.Fatalf("waiting consumer got %v", err)
+	}
+
+	q = NewBlockingQueue[int](1)
+	q.Push(1)
+	go func() {
+		errs <- q.PushCtx(context.Background(), 2)
+	}()
+	awaitWaiters(q, &q.producers, 1, t)
+	q.Close()
+	q.Close()
+	if err := <-errs; err != ErrQueueClosed {
+		t.Fatalf("waiting producer got %v", err)
+	}
+	if err := q.TryPush(3); err != ErrQueueClosed {
+		t.Fatalf("push after closing got %v", err)
+	}
+
+	// remaining items drain before the queue reports being closed
+	if item, err := q.Pop(); item != 1 || err != nil {
+		t.Fatalf("popped %v, %v after closing", item, err)
+	}
+	if _, err := q.Pop(); err != ErrQueueClosed {
+		t.Fatalf("drained queue got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Push on a closed queue did not panic")
+		}
+	}()
+	q.Push(4)
+}
+
+// Blocked callers are served in arrival order
+func TestBlockingQueueFairness(t *testing.T) {
+	const n = 10
+	q := NewBlockingQueue[int](1)
+	received := make([]chan int, n)
+	for i := range received {
+		received[i] = make(chan int, 1)
+		go func(i int) {
+			item, _ := q.Pop()
+			received[i] <- item
+		}(i)
+		awaitWaiters(q, &q.consumers, i+1, t)
+	}
+	for i := 0; i < n; i++ {
+		q.Push(i)
+	}
+	for i := range received {
+		if item := <-received[i]; item != i {
+			t.Fatalf("consumer %v received %v", i, item)
+		}
+	}
+
+	q.Push(-1)
+	for i := 0; i < n; i++ {
+		go q.Push(i)
+		awaitWaiters(q, &q.producers, i+1, t)
+	}
+	for expected := -1; expected < n; expected++ {
+		if item, _ := q.Pop(); item != expected {
+			t.Fatalf("popped %v, expected %v", item, expected)
+		}
+	}
+}
+
+func TestBlockingQueueConcurrent(t *testing.T) {
+	const producers, consumers, items = 8, 8, 2000
+	type message struct{ producer, sequence int }
+	q := NewBlockingQueue[message](16)
+
+	var producing sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func(p int) {
+			defer producing.Done()
+			for i := 0; i < items; i++ {
+				q.Push(message{p, i})
+			}
+		}(p)
+	}
+
+	received := make([][]message, consumers)
+	var consuming sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		consuming.Add(1)
+		go func(c int) {
+			defer consuming.Done()
+			for {
+				item, err := q.Pop()
+				if err == ErrQueueClosed {
+					return
+				}
+				received[c] = append(received[c], item)
+			}
+		}(c)
+	}
+	producing.Wait()
+	q.Close()
+	consuming.Wait()
+
+	seen := map[message]bool{}
+	for c, messages := range received {
+		// FIFO order means every consumer sees each producer's messages in sequence
+		last := make([]int, producers)
+		for p := range last {
+			last[p] = -1
+		}
+		for _, m := range messages {
+			if seen[m] || m.sequence <= last[m.producer] {
+				t.Fatalf("consumer %v received %v out of order", c, m)
+			}
+			seen[m] = true
+			last[m.producer] = m.sequence
+		}
+	}
+	if len(seen) != producers*items {
+		t.Fatalf("received %v messages, expected %v", len(seen), producers*items)
+	}
+}
@@ -0,0 +1,95 @@
+package collections
+
+import (
+	"sync"
+	"time"
+)
+
+// Source of time for the scheduling collections, so that tests can control it
+type Clock interface {
+	Now() time.Time
+	// Channel that receives the time once d has passed, and a function that
+	// stops the timer
+	Timer(d time.Duration) (<-chan time.Time, func())
+}
+
+// The real time
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Timer(d time.Duration) (<-chan time.Time, func()) {
+	timer := time.NewTimer(d)
+	return timer.C, func() { timer.Stop() }
+}
+
+// Clock that only moves when told to, safe for concurrent use
+type ManualClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Timer(d time.Duration) (<-chan time.Time, func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer := &manualTimer{c.now.Add(d), make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.c <- c.now
+		return timer.c, func() {}
+	}
+	c.timers = append(c.timers, timer)
+	return timer.c, func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		for i, other := range c.timers {
+			if other == timer {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Moves the clock forward and fires the timers that are due
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.c <- c.now
+	}
+	clear(c.timers[len(pending):])
+	c.timers = pending
+}
+
+// Number of timers waiting to fire, so that tests can wait for a goroutine to block
+func (c *ManualClock) Timers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
@@ -0,0 +1,116 @@
+package collections
+
+// Array based min-heap in which every node has d children. A higher arity
+// makes pushes and priority decreases cheaper and pops dearer.
+type DaryHeap[T, P any] struct {
+	handles []*PriorityHandle[T, P]
+	compare func(a, b P) int
+	arity   int
+	owner   *heapOwner
+}
+
+func NewDaryHeap[T, P any](arity int, compare func(a, b P) int) *DaryHeap[T, P] {
+	if arity < 2 {
+		panic("DaryHeap arity must be at least 2")
+	}
+	return &DaryHeap[T, P]{compare: compare, arity: arity, owner: &heapOwner{}}
+}
+
+func (q *DaryHeap[T, P]) Push(value T, priority P) *PriorityHandle[T, P] {
+	handle := &PriorityHandle[T, P]{Value: value, priority: priority, index: len(q.handles), owner: q.owner}
+	q.handles = append(q.handles, handle)
+	q.up(handle.index)
+	return handle
+}
+
+func (q *DaryHeap[T, P]) Pop() (T, P, error) {
+	if len(q.handles) == 0 {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	handle := q.handles[0]
+	q.Remove(handle)
+	return handle.Value, handle.priority, nil
+}
+
+func (q *DaryHeap[T, P]) Peek() (T, P, error) {
+	if len(q.handles) == 0 {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	return q.handles[0].Value, q.handles[0].priority, nil
+}
+
+// Reports whether the handle's value is still queued
+func (q *DaryHeap[T, P]) Contains(handle *PriorityHandle[T, P]) bool {
+	return handle.in(q.owner)
+}
+
+// Lowers the priority of a queued value, O(log_d(n)).
+// Fails if the new priority would come after the current one.
+func (q *DaryHeap[T, P]) DecreaseKey(handle *PriorityHandle[T, P], priority P) error {
+	if !q.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	if q.compare(priority, handle.priority) > 0 {
+		return ErrPriorityIncreased
+	}
+	handle.priority = priority
+	q.up(handle.index)
+	return nil
+}
+
+// Changes the priority of a queued value in either direction
+func (q *DaryHeap[T, P]) Update(handle *PriorityHandle[T, P], priority P) error {
+	if !q.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	handle.priority = priority
+	if !q.down(handle.index) {
+		q.up(handle.index)
+	}
+	return nil
+}
+
+func (q *DaryHeap[T, P]) Remove(handle *PriorityHandle[T, P]) error {
+	if !q.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	index, last := handle.index, len(q.handles)-1
+	q.swap(index, last)
+	q.handles[last] = nil
+	q.handles = q.handles[:last]
+	handle.index = -1
+	if index < last && !q.down(index) {
+		q.up(index)
+	}
+	return nil
+}
+
+func (q *DaryHeap[T, P]) Count() int {
+	return len(q.handles)
+}
+
+func (q *DaryHeap[T, P]) Empty() bool {
+	return len(q.handles) == 0
+}
+
+func (q *DaryHeap[T, P]) less(i, j int) bool {
+	return q.compare(q.handles[i].priority, q.handles[j].priority) < 0
+}
+
+func (q *DaryHeap[T, P]) swap(i, j int) {
+	q.handles[i], q.handles[j] = q.handles[j], q.handles[i]
+	q.handles[i].index = i
+	q.handles[j].index = j
+}
+
+func (q *DaryHeap[T, P]) up(i int) {
+	siftUp(i, q.arity, q.less, q.swap)
+}
+
+func (q *DaryHeap[T, P]) down(i int) bool {
+	return siftDown(i, len(q.handles), q.arity, q.less, q.swap)
+}
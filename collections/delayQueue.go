@@ -0,0 +1,170 @@
+package collections
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrNotDue = errors.New("No item is due")
+
+// Queue of items that can only be popped once their deadline has passed,
+// earliest deadline first, safe for concurrent use. Backed by a binary heap,
+// so scheduling, cancelling and popping take O(log(n)).
+type DelayQueue[T any] struct {
+	mutex  sync.Mutex
+	items  *IndexedPriorityQueue[T, time.Time]
+	clock  Clock
+	closed bool
+	// Closed and replaced when the earliest deadline moves forward or the
+	// queue is closed, to wake the waiting pops
+	changed chan struct{}
+}
+
+// Queue reading the time from clock, SystemClock if nil
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &DelayQueue[T]{
+		items:   NewIndexedPriorityQueue[T](time.Time.Compare),
+		clock:   clock,
+		changed: make(chan struct{}),
+	}
+}
+
+// Schedules the item for the given deadline. The handle can cancel or
+// reschedule it until it is popped. Panics if the queue is closed.
+func (q *DelayQueue[T]) PushAt(item T, deadline time.Time) *PriorityHandle[T, time.Time] {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.closed {
+		panic(ErrQueueClosed)
+	}
+	handle := q.items.Push(item, deadline)
+	q.notify(handle)
+	return handle
+}
+
+// Schedules the item once the delay has passed from now
+func (q *DelayQueue[T]) PushAfter(item T, delay time.Duration) *PriorityHandle[T, time.Time] {
+	return q.PushAt(item, q.clock.Now().Add(delay))
+}
+
+// Moves a scheduled item to a new deadline
+func (q *DelayQueue[T]) Reschedule(handle *PriorityHandle[T, time.Time], deadline time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if err := q.items.Update(handle, deadline); err != nil {
+		return err
+	}
+	q.notify(handle)
+	return nil
+}
+
+// Removes a scheduled item before it is popped
+func (q *DelayQueue[T]) Cancel(handle *PriorityHandle[T, time.Time]) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.items.Remove(handle)
+}
+
+// Waits for the earliest item to become due and pops it.
+// Returns ErrQueueClosed once the queue is closed and no item is due.
+func (q *DelayQueue[T]) Pop() (T, error) {
+	return q.PopCtx(context.Background())
+}
+
+// Pop that gives up with the context's error once the context is done
+func (q *DelayQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	for {
+		q.mutex.Lock()
+		item, err := q.tryPop()
+		if err != ErrNotDue && err != ErrQueueEmpty {
+			q.mutex.Unlock()
+			return item, err
+		}
+		if q.closed {
+			q.mutex.Unlock()
+			return item, ErrQueueClosed
+		}
+		changed := q.changed
+		var due <-chan time.Time
+		stop := func() {}
+		if err == ErrNotDue {
+			_, deadline, _ := q.items.Peek()
+			due, stop = q.clock.Timer(deadline.Sub(q.clock.Now()))
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-due:
+		case <-ctx.Done():
+			stop()
+			return item, ctx.Err()
+		}
+		stop()
+	}
+}
+
+// Pops the earliest item if it is due, ErrNotDue if it is not
+// and ErrQueueEmpty if there are no items
+func (q *DelayQueue[T]) TryPop() (T, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.tryPop()
+}
+
+// Earliest item and its deadline, whether due or not
+func (q *DelayQueue[T]) Peek() (T, time.Time, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	item, deadline, err := q.items.Peek()
+	if err != nil {
+		return item, deadline, ErrQueueEmpty
+	}
+	return item, deadline, nil
+}
+
+// Wakes the waiting pops, which return ErrQueueClosed unless an item is due.
+// Scheduled items stay available to TryPop. Closing twice has no effect.
+func (q *DelayQueue[T]) Close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.changed)
+	}
+}
+
+// Number of scheduled items, due or not
+func (q *DelayQueue[T]) Count() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.items.Count()
+}
+
+// Must hold the mutex
+func (q *DelayQueue[T]) tryPop() (T, error) {
+	item, deadline, err := q.items.Peek()
+	if err != nil {
+		return item, ErrQueueEmpty
+	}
+	if deadline.After(q.clock.Now()) {
+		var zero T
+		return zero, ErrNotDue
+	}
+	q.items.Pop()
+	return item, nil
+}
+
+// Wakes the waiting pops if the handle became the earliest item. Must hold the mutex.
+func (q *DelayQueue[T]) notify(handle *PriorityHandle[T, time.Time]) {
+	if q.closed || q.items.handles[0] != handle {
+		return
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
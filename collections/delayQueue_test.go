@@ -0,0 +1,133 @@
+package collections
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Waits until n goroutines wait on the clock's timers
+func awaitTimers(clock *ManualClock, n int, t *testing.T) {
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.Timers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%v timers waiting, expected %v", clock.Timers(), n)
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestDelayQueue(t *testing.T) {
+	clock := NewManualClock(epoch)
+	q := NewDelayQueue[string](clock)
+	if _, err := q.TryPop(); err != ErrQueueEmpty {
+		t.Fatalf("pop from an empty queue: %v", err)
+	}
+	q.PushAfter("c", 3*time.Second)
+	a := q.PushAfter("a", time.Second)
+	q.PushAt("b", epoch.Add(2*time.Second))
+	cancelled := q.PushAfter("x", 1500*time.Millisecond)
+	if _, err := q.TryPop(); err != ErrNotDue {
+		t.Fatalf("popped before the deadline: %v", err)
+	}
+	if item, deadline, _ := q.Peek(); item != "a" || !deadline.Equal(epoch.Add(time.Second)) || a.Priority() != deadline {
+		t.Fatalf("peeked %v due %v", item, deadline)
+	}
+	if err := q.Cancel(cancelled); err != nil || q.Cancel(cancelled) == nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	clock.Advance(time.Second)
+	if item, err := q.TryPop(); err != nil || item != "a" {
+		t.Fatalf("popped %v: %v", item, err)
+	}
+	if _, err := q.TryPop(); err != ErrNotDue {
+		t.Fatalf("popped b early: %v", err)
+	}
+	clock.Advance(5 * time.Second)
+	for _, expected := range []string{"b", "c"} {
+		if item, err := q.TryPop(); err != nil || item != expected {
+			t.Fatalf("popped %v, expected %v: %v", item, expected, err)
+		}
+	}
+	if q.Count() != 0 || q.Cancel(a) == nil {
+		t.Fatalf("%v items left", q.Count())
+	}
+}
+
+func TestDelayQueueBlockingPop(t *testing.T) {
+	clock := NewManualClock(epoch)
+	q := NewDelayQueue[int](clock)
+	popped := make(chan int)
+	go func() {
+		for {
+			item, err := q.Pop()
+			if err != nil {
+				close(popped)
+				return
+			}
+			popped <- item
+		}
+	}()
+
+	late := q.PushAfter(2, 10*time.Second)
+	awaitTimers(clock, 1, t)
+	// an earlier item wakes the waiting pop, which then waits for it instead
+	q.PushAfter(1, 5*time.Second)
+	clock.Advance(4 * time.Second)
+	select {
+	case item := <-popped:
+		t.Fatalf("popped %v before its deadline", item)
+	default:
+	}
+	clock.Advance(time.Second)
+	if item := <-popped; item != 1 {
+		t.Fatalf("popped %v, expected 1", item)
+	}
+	// rescheduling wakes the pop as well
+	awaitTimers(clock, 1, t)
+	if err := q.Reschedule(late, epoch); err != nil {
+		t.Fatal(err)
+	}
+	if item := <-popped; item != 2 {
+		t.Fatalf("popped %v, expected 2", item)
+	}
+	awaitTimers(clock, 0, t)
+	q.Close()
+	if _, open := <-popped; open {
+		t.Fatalf("pop not woken by Close")
+	}
+}
+
+func TestDelayQueueContext(t *testing.T) {
+	clock := NewManualClock(epoch)
+	q := NewDelayQueue[int](clock)
+	q.PushAfter(1, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error)
+	go func() {
+		_, err := q.PopCtx(ctx)
+		result <- err
+	}()
+	awaitTimers(clock, 1, t)
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Fatalf("pop returned %v", err)
+	}
+	// the timer of the abandoned pop is stopped
+	if clock.Timers() != 0 || q.Count() != 1 {
+		t.Fatalf("%v timers and %v items left", clock.Timers(), q.Count())
+	}
+}
+
+func TestDelayQueueSystemClock(t *testing.T) {
+	q := NewDelayQueue[int](nil)
+	start := time.Now()
+	q.PushAfter(1, 20*time.Millisecond)
+	if item, err := q.Pop(); err != nil || item != 1 || time.Since(start) < 20*time.Millisecond {
+		t.Fatalf("popped %v after %v: %v", item, time.Since(start), err)
+	}
+}
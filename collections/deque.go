@@ -0,0 +1,248 @@
+package collections
+
+import (
+	"cmp"
+	"errors"
+)
+
+// Number of items per chunk of a Deque
+const dequeChunkSize = 64
+
+// Smallest non-empty chunk ring, the deque never shrinks below it
+const minDequeChunks = 4
+
+// Double-ended queue on a ring of fixed size chunks. Pushes and pops at either
+// end take O(1) time and never move items, so growing costs one chunk at a time.
+// A Queue works like PushBack with PopFront, a Stack like PushBack with PopBack.
+// The zero value is an empty deque.
+type Deque[T any] struct {
+	// Ring of chunks, the used ones start at first
+	chunks []*[dequeChunkSize]T
+	first  int
+	// Position of the front item in the first chunk
+	head  int
+	count int
+	// Last emptied chunk, kept so that churn at a chunk boundary does not allocate
+	spare *[dequeChunkSize]T
+}
+
+func (d *Deque[T]) PushBack(item T) {
+	end := d.head + d.count
+	if end == d.usedChunks()*dequeChunkSize {
+		d.addChunk(false)
+	}
+	d.chunk(end / dequeChunkSize)[end%dequeChunkSize] = item
+	d.count++
+}
+
+func (d *Deque[T]) PushFront(item T) {
+	if d.head == 0 {
+		d.addChunk(true)
+		d.head = dequeChunkSize
+	}
+	d.head--
+	d.chunks[d.first][d.head] = item
+	d.count++
+}
+
+func (d *Deque[T]) PopFront() (T, error) {
+	var item T
+	if d.count == 0 {
+		return item, errors.New("Deque is empty")
+	}
+	chunk := d.chunks[d.first]
+	item = chunk[d.head]
+	// drop the reference so that the popped item can be collected
+	var zero T
+	chunk[d.head] = zero
+	d.head++
+	d.count--
+	if d.head == dequeChunkSize || d.count == 0 {
+		d.head = 0
+		d.removeChunk(true)
+	}
+	return item, nil
+}
+
+func (d *Deque[T]) PopBack() (T, error) {
+	var item T
+	if d.count == 0 {
+		return item, errors.New("Deque is empty")
+	}
+	d.count--
+	end := d.head + d.count
+	chunk := d.chunk(end / dequeChunkSize)
+	item = chunk[end%dequeChunkSize]
+	var zero T
+	chunk[end%dequeChunkSize] = zero
+	if d.count == 0 {
+		d.head = 0
+	}
+	if end%dequeChunkSize == 0 || d.count == 0 {
+		d.removeChunk(false)
+	}
+	return item, nil
+}
+
+func (d *Deque[T]) PeekFront() (T, error) {
+	var item T
+	if d.count == 0 {
+		return item, errors.New("Deque is empty")
+	}
+	return d.chunks[d.first][d.head], nil
+}
+
+func (d *Deque[T]) PeekBack() (T, error) {
+	var item T
+	if d.count == 0 {
+		return item, errors.New("Deque is empty")
+	}
+	end := d.head + d.count - 1
+	return d.chunk(end / dequeChunkSize)[end%dequeChunkSize], nil
+}
+
+// Item at the given position from the front, O(1)
+func (d *Deque[T]) At(index int) (T, error) {
+	var item T
+	if index < 0 || index >= d.count {
+		return item, errors.New("Index is out of range")
+	}
+	position := d.head + index
+	return d.chunk(position / dequeChunkSize)[position%dequeChunkSize], nil
+}
+
+// Replaces the item at the given position from the front, O(1)
+func (d *Deque[T]) Set(index int, item T) error {
+	if index < 0 || index >= d.count {
+		return errors.New("Index is out of range")
+	}
+	position := d.head + index
+	d.chunk(position / dequeChunkSize)[position%dequeChunkSize] = item
+	return nil
+}
+
+// Moves the last n items to the front, or the first -n items to the back if n
+// is negative, O(min(n, Count()-n))
+func (d *Deque[T]) Rotate(n int) {
+	if d.count < 2 {
+		return
+	}
+	n %= d.count
+	if n < 0 {
+		n += d.count
+	}
+	if n <= d.count/2 {
+		for i := 0; i < n; i++ {
+			item, _ := d.PopBack()
+			d.PushFront(item)
+		}
+		return
+	}
+	for i := n; i < d.count; i++ {
+		item, _ := d.PopFront()
+		d.PushBack(item)
+	}
+}
+
+// Calls visit with every item from front to back until it returns false.
+// The deque must not be modified during the iteration.
+func (d *Deque[T]) Each(visit func(index int, item T) bool) {
+	for i := 0; i < d.count; {
+		position := d.head + i
+		chunk := d.chunk(position / dequeChunkSize)
+		for offset := position % dequeChunkSize; offset < dequeChunkSize && i < d.count; offset++ {
+			if !visit(i, chunk[offset]) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (d *Deque[T]) Count() int {
+	return d.count
+}
+
+func (d *Deque[T]) Empty() bool {
+	return d.count == 0
+}
+
+// Removes all items and releases the chunks
+func (d *Deque[T]) Clear() {
+	*d = Deque[T]{}
+}
+
+// Number of chunks holding items
+func (d *Deque[T]) usedChunks() int {
+	return (d.head + d.count + dequeChunkSize - 1) / dequeChunkSize
+}
+
+// The i-th used chunk
+func (d *Deque[T]) chunk(i int) *[dequeChunkSize]T {
+	return d.chunks[(d.first+i)%len(d.chunks)]
+}
+
+// Adds an empty chunk before the first or after the last used one
+func (d *Deque[T]) addChunk(front bool) {
+	used := d.usedChunks()
+	if used == len(d.chunks) {
+		d.resize(max(minDequeChunks, 2*len(d.chunks)))
+	}
+	chunk := d.spare
+	d.spare = nil
+	if chunk == nil {
+		chunk = new([dequeChunkSize]T)
+	}
+	if front {
+		d.first = (d.first + len(d.chunks) - 1) % len(d.chunks)
+		d.chunks[d.first] = chunk
+		return
+	}
+	d.chunks[(d.first+used)%len(d.chunks)] = chunk
+}
+
+// Drops the empty first or last chunk, called once head and count no longer cover it
+func (d *Deque[T]) removeChunk(front bool) {
+	index := d.first
+	if front {
+		d.first = (d.first + 1) % len(d.chunks)
+	} else {
+		index = (d.first + d.usedChunks()) % len(d.chunks)
+	}
+	d.spare = d.chunks[index]
+	d.chunks[index] = nil
+	if len(d.chunks) > minDequeChunks && d.usedChunks() <= len(d.chunks)/4 {
+		d.resize(len(d.chunks) / 2)
+	}
+}
+
+// Moves the used chunks to the front of a new ring of the given size
+func (d *Deque[T]) resize(size int) {
+	chunks := make([]*[dequeChunkSize]T, size)
+	for i := 0; i < d.usedChunks(); i++ {
+		chunks[i] = d.chunk(i)
+	}
+	d.chunks = chunks
+	d.first = 0
+}
+
+// Largest item of every window of the given size sliding over items, O(len(items)).
+// Panics if the window size is not positive.
+func SlidingWindowMax[T cmp.Ordered](items []T, window int) []T {
+	if window < 1 {
+		panic("SlidingWindowMax window size must be positive")
+	}
+	result := make([]T, 0, max(0, len(items)-window+1))
+	current := MonotonicQueue[T]{}
+	for _, item := range items {
+		current.Push(item)
+		if current.Count() > window {
+			current.Pop()
+		}
+		if current.Count() == window {
+			largest, _ := current.Max()
+			result = append(result, largest)
+		}
+	}
+	return result
+}
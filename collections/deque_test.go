@@ -0,0 +1,194 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Compares the deque with a slice holding the same items
+func checkDeque(d *Deque[int], model []int, t *testing.T) {
+	if d.Count() != len(model) || d.Empty() != (len(model) == 0) {
+		t.Fatalf("count %v, expected %v", d.Count(), len(model))
+	}
+	items := []int{}
+	d.Each(func(index, item int) bool {
+		if index != len(items) {
+			t.Fatalf("visited index %v after %v items", index, len(items))
+		}
+		items = append(items, item)
+		return true
+	})
+	if !slices.Equal(items, model) {
+		t.Fatalf("deque holds %v, expected %v", items, model)
+	}
+	if d.usedChunks() > len(d.chunks) || len(d.chunks) > max(minDequeChunks, 4*(d.usedChunks()+1)) {
+		t.Fatalf("%v chunks in a ring of %v", d.usedChunks(), len(d.chunks))
+	}
+}
+
+func TestDeque(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	d := Deque[int]{}
+	model := []int{}
+	for i := 0; i < 20000; i++ {
+		// drift between growing and shrinking phases
+		grow := (i/2000)%2 == 0
+		switch op := random.Intn(10); {
+		case op < 2 || op < 4 && grow:
+			d.PushBack(i)
+			model = append(model, i)
+		case op < 4 || op < 6 && grow:
+			d.PushFront(i)
+			model = append([]int{i}, model...)
+		case op < 7:
+			item, err := d.PopFront()
+			if len(model) == 0 {
+				if err == nil {
+					t.Fatalf("popped %v from an empty deque", item)
+				}
+				continue
+			}
+			if err != nil || item != model[0] {
+				t.Fatalf("popped %v from the front, expected %v", item, model[0])
+			}
+			model = model[1:]
+		case op < 9:
+			item, err := d.PopBack()
+			if len(model) == 0 {
+				if err == nil {
+					t.Fatalf("popped %v from an empty deque", item)
+				}
+				continue
+			}
+			if err != nil || item != model[len(model)-1] {
+				t.Fatalf("popped %v from the back, expected %v", item, model[len(model)-1])
+			}
+			model = model[:len(model)-1]
+		default:
+			if len(model) == 0 {
+				continue
+			}
+			index := random.Intn(len(model))
+			if item, err := d.At(index); err != nil || item != model[index] {
+				t.Fatalf("item %v at %v, expected %v", item, index, model[index])
+			}
+			d.Set(index, -i)
+			model[index] = -i
+		}
+		if i%50 == 0 {
+			checkDeque(&d, model, t)
+		}
+	}
+	checkDeque(&d, model, t)
+
+	if front, _ := d.PeekFront(); len(model) > 0 && front != model[0] {
+		t.Fatalf("front %v, expected %v", front, model[0])
+	}
+	if back, _ := d.PeekBack(); len(model) > 0 && back != model[len(model)-1] {
+		t.Fatalf("back %v, expected %v", back, model[len(model)-1])
+	}
+	for _, index := range []int{-1, len(model)} {
+		if _, err := d.At(index); err == nil {
+			t.Fatalf("read index %v of %v items", index, len(model))
+		}
+		if err := d.Set(index, 0); err == nil {
+			t.Fatalf("wrote index %v of %v items", index, len(model))
+		}
+	}
+	d.Clear()
+	if _, err := d.PeekBack(); err == nil || !d.Empty() {
+		t.Fatalf("cleared deque holds %v items", d.Count())
+	}
+}
+
+func TestDequeRotate(t *testing.T) {
+	d := Deque[int]{}
+	model := []int{}
+	for i := 0; i < 300; i++ {
+		d.PushBack(i)
+		model = append(model, i)
+	}
+	for _, n := range []int{1, -1, 5, 200, -299, 600, 0, 150, 151, 149} {
+		d.Rotate(n)
+		shift := ((n % len(model)) + len(model)) % len(model)
+		model = append(model[len(model)-shift:], model[:len(model)-shift]...)
+		checkDeque(&d, model, t)
+	}
+
+	single := Deque[int]{}
+	single.Rotate(3)
+	single.PushFront(1)
+	single.Rotate(-2)
+	checkDeque(&single, []int{1}, t)
+}
+
+func TestDequeEachStops(t *testing.T) {
+	d := Deque[int]{}
+	for i := 0; i < 200; i++ {
+		d.PushFront(i)
+	}
+	visited := 0
+	d.Each(func(index, item int) bool {
+		visited++
+		return index < 100
+	})
+	if visited != 101 {
+		t.Fatalf("visited %v items", visited)
+	}
+}
+
+// Deque as a queue and as a stack next to the dedicated types
+func TestDequeAsQueueAndStack(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+	asQueue, asStack := Deque[int]{}, Deque[int]{}
+	queue, stack := Queue[int]{}, Stack[int]{}
+	for i := 0; i < 5000; i++ {
+		if random.Intn(3) > 0 {
+			asQueue.PushBack(i)
+			queue.Push(i)
+			asStack.PushBack(i)
+			stack.Push(i)
+			continue
+		}
+		a, errA := asQueue.PopFront()
+		b, errB := queue.Pop()
+		c, errC := asStack.PopBack()
+		d, errD := stack.Pop()
+		if a != b || c != d || (errA == nil) != (errB == nil) || (errC == nil) != (errD == nil) {
+			t.Fatalf("deque popped %v and %v, queue %v, stack %v", a, c, b, d)
+		}
+	}
+}
+
+func TestSlidingWindowMax(t *testing.T) {
+	random := rand.New(rand.NewSource(9))
+	items := make([]int, 500)
+	for i := range items {
+		items[i] = random.Intn(100)
+	}
+	for _, window := range []int{1, 2, 7, 64, 499, 500, 501} {
+		expected := []int{}
+		for i := 0; i+window <= len(items); i++ {
+			expected = append(expected, slices.Max(items[i:i+window]))
+		}
+		if result := SlidingWindowMax(items, window); !slices.Equal(result, expected) {
+			t.Fatalf("window %v: %v, expected %v", window, result, expected)
+		}
+	}
+}
+
+func BenchmarkDequeChurn(b *testing.B) {
+	d := Deque[int]{}
+	for i := 0; i < 1000; i++ {
+		d.PushBack(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.PushBack(i)
+		d.PopFront()
+		d.PushFront(i)
+		d.PopBack()
+	}
+}
@@ -0,0 +1,306 @@
+package collections
+
+import "mayerus/csgo/internal/ordered"
+
+// Directed graph
+type DiGraph[T comparable] struct {
+	Counter  int
+	Vertices map[int]*DiVertex[T]
+}
+
+// Weighted directed graph
+type WDiGraph[T comparable] struct {
+	Counter  int
+	Vertices map[int]*WDiVertex[T]
+}
+
+// Directed graph vertex.
+// Out holds the heads of the vertex's out-edges, In the tails of its in-edges.
+type DiVertex[T comparable] struct {
+	Value T
+	Out   map[int]*DiVertex[T]
+	In    map[int]*DiVertex[T]
+}
+
+// Weighted directed graph vertex.
+// Out holds the vertex's out-arcs, In its in-arcs.
+type WDiVertex[T comparable] struct {
+	Value T
+	Out   map[int]*Arc[T]
+	In    map[int]*Arc[T]
+}
+
+// Weighted directed graph edge.
+// Vertex is the far end of the arc: the head for an out-arc, the tail for an in-arc.
+type Arc[T comparable] struct {
+	Weight float64
+	Vertex *WDiVertex[T]
+}
+
+var (
+	_ WeightedGrapher[int] = (*Graph[int])(nil)
+	_ WeightedGrapher[int] = (*WGraph[int])(nil)
+	_ WeightedGrapher[int] = (*DiGraph[int])(nil)
+	_ WeightedGrapher[int] = (*WDiGraph[int])(nil)
+)
+
+func (g *DiGraph[T]) AddVertex(value T) int {
+	if g.Vertices == nil {
+		g.Vertices = map[int]*DiVertex[T]{}
+	}
+	g.Counter++
+	g.Vertices[g.Counter] = &DiVertex[T]{value, map[int]*DiVertex[T]{}, map[int]*DiVertex[T]{}}
+	return g.Counter
+}
+
+func (g *WDiGraph[T]) AddVertex(value T) int {
+	if g.Vertices == nil {
+		g.Vertices = map[int]*WDiVertex[T]{}
+	}
+	g.Counter++
+	g.Vertices[g.Counter] = &WDiVertex[T]{value, map[int]*Arc[T]{}, map[int]*Arc[T]{}}
+	return g.Counter
+}
+
+// Adds the edge from -> to
+func (g *DiGraph[T]) AddEdge(from, to int) error {
+	if _, ok := g.Vertices[from]; !ok {
+		return &UnknownVertexError{from}
+	}
+	if _, ok := g.Vertices[to]; !ok {
+		return &UnknownVertexError{to}
+	}
+	g.Vertices[from].Out[to] = g.Vertices[to]
+	g.Vertices[to].In[from] = g.Vertices[from]
+	return nil
+}
+
+// Adds the arc from -> to
+func (g *WDiGraph[T]) AddEdge(from, to int, weight float64) error {
+	if _, ok := g.Vertices[from]; !ok {
+		return &UnknownVertexError{from}
+	}
+	if _, ok := g.Vertices[to]; !ok {
+		return &UnknownVertexError{to}
+	}
+	g.Vertices[from].Out[to] = &Arc[T]{weight, g.Vertices[to]}
+	g.Vertices[to].In[from] = &Arc[T]{weight, g.Vertices[from]}
+	return nil
+}
+
+func (g *DiGraph[T]) HasVertex(id int) bool {
+	_, ok := g.Vertices[id]
+	return ok
+}
+
+func (g *WDiGraph[T]) HasVertex(id int) bool {
+	_, ok := g.Vertices[id]
+	return ok
+}
+
+func (g *DiGraph[T]) VertexCount() int {
+	return len(g.Vertices)
+}
+
+func (g *WDiGraph[T]) VertexCount() int {
+	return len(g.Vertices)
+}
+
+func (g *DiGraph[T]) VertexIDs() []int {
+	return ordered.Keys(g.Vertices)
+}
+
+func (g *WDiGraph[T]) VertexIDs() []int {
+	return ordered.Keys(g.Vertices)
+}
+
+func (g *DiGraph[T]) Successors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.Out)
+	}
+	return nil
+}
+
+func (g *WDiGraph[T]) Successors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.Out)
+	}
+	return nil
+}
+
+func (g *DiGraph[T]) Predecessors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.In)
+	}
+	return nil
+}
+
+func (g *WDiGraph[T]) Predecessors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.In)
+	}
+	return nil
+}
+
+func (g *DiGraph[T]) Directed() bool {
+	return true
+}
+
+func (g *WDiGraph[T]) Directed() bool {
+	return true
+}
+
+// Unweighted edges weigh 1
+func (g *DiGraph[T]) Weight(from, to int) (float64, error) {
+	if err := g.checkEdge(from, to); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Returns the weight of the arc from -> to
+func (g *WDiGraph[T]) Weight(from, to int) (float64, error) {
+	if err := g.checkEdge(from, to); err != nil {
+		return 0, err
+	}
+	return g.Vertices[from].Out[to].Weight, nil
+}
+
+func (g *DiGraph[T]) checkEdge(from, to int) error {
+	if _, ok := g.Vertices[from]; !ok {
+		return &UnknownVertexError{from}
+	}
+	if _, ok := g.Vertices[to]; !ok {
+		return &UnknownVertexError{to}
+	}
+	if _, ok := g.Vertices[from].Out[to]; !ok {
+		return &UnknownEdgeError{from, to}
+	}
+	return nil
+}
+
+func (g *WDiGraph[T]) checkEdge(from, to int) error {
+	if _, ok := g.Vertices[from]; !ok {
+		return &UnknownVertexError{from}
+	}
+	if _, ok := g.Vertices[to]; !ok {
+		return &UnknownVertexError{to}
+	}
+	if _, ok := g.Vertices[from].Out[to]; !ok {
+		return &UnknownEdgeError{from, to}
+	}
+	return nil
+}
+
+// Returns the number of edges leading into the vertex
+func (g *DiGraph[T]) InDegree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	return len(vertex.In), nil
+}
+
+// Returns the number of arcs leading into the vertex
+func (g *WDiGraph[T]) InDegree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	return len(vertex.In), nil
+}
+
+// Returns the number of edges leaving the vertex
+func (g *DiGraph[T]) OutDegree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	return len(vertex.Out), nil
+}
+
+// Returns the number of arcs leaving the vertex
+func (g *WDiGraph[T]) OutDegree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	return len(vertex.Out), nil
+}
+
+// Returns a new graph with every edge reversed.
+// Vertex IDs and values are preserved, g is left untouched.
+func (g *DiGraph[T]) Reverse() *DiGraph[T] {
+	reversed := &DiGraph[T]{Counter: g.Counter, Vertices: map[int]*DiVertex[T]{}}
+	for id, vertex := range g.Vertices {
+		reversed.Vertices[id] = &DiVertex[T]{vertex.Value, map[int]*DiVertex[T]{}, map[int]*DiVertex[T]{}}
+	}
+	for from, vertex := range g.Vertices {
+		for to := range vertex.Out {
+			reversed.Vertices[to].Out[from] = reversed.Vertices[from]
+			reversed.Vertices[from].In[to] = reversed.Vertices[to]
+		}
+	}
+	return reversed
+}
+
+// Returns a new graph with every arc reversed.
+// Vertex IDs, values and weights are preserved, g is left untouched.
+func (g *WDiGraph[T]) Reverse() *WDiGraph[T] {
+	reversed := &WDiGraph[T]{Counter: g.Counter, Vertices: map[int]*WDiVertex[T]{}}
+	for id, vertex := range g.Vertices {
+		reversed.Vertices[id] = &WDiVertex[T]{vertex.Value, map[int]*Arc[T]{}, map[int]*Arc[T]{}}
+	}
+	for from, vertex := range g.Vertices {
+		for to, arc := range vertex.Out {
+			reversed.Vertices[to].Out[from] = &Arc[T]{arc.Weight, reversed.Vertices[from]}
+			reversed.Vertices[from].In[to] = &Arc[T]{arc.Weight, reversed.Vertices[to]}
+		}
+	}
+	return reversed
+}
+
+// Reverses every edge of the graph in place (transposition) in O(|V|)
+func (g *DiGraph[T]) Transpose() {
+	for _, vertex := range g.Vertices {
+		vertex.In, vertex.Out = vertex.Out, vertex.In
+	}
+}
+
+// Reverses every arc of the graph in place (transposition) in O(|V|)
+func (g *WDiGraph[T]) Transpose() {
+	for _, vertex := range g.Vertices {
+		vertex.In, vertex.Out = vertex.Out, vertex.In
+	}
+}
+
+// Returns the directed graph with an edge in both directions
+// for every edge of g. Vertex IDs and values are preserved.
+func (g *Graph[T]) ToDiGraph() *DiGraph[T] {
+	directed := &DiGraph[T]{Counter: g.Counter, Vertices: map[int]*DiVertex[T]{}}
+	for id, vertex := range g.Vertices {
+		directed.Vertices[id] = &DiVertex[T]{vertex.Value, map[int]*DiVertex[T]{}, map[int]*DiVertex[T]{}}
+	}
+	for from, vertex := range g.Vertices {
+		for to := range vertex.Edges {
+			directed.AddEdge(from, to)
+		}
+	}
+	return directed
+}
+
+// Returns the weighted directed graph with an arc in both directions
+// for every edge of g, each carrying the edge's weight.
+// Vertex IDs and values are preserved.
+func (g *WGraph[T]) ToWDiGraph() *WDiGraph[T] {
+	directed := &WDiGraph[T]{Counter: g.Counter, Vertices: map[int]*WDiVertex[T]{}}
+	for id, vertex := range g.Vertices {
+		directed.Vertices[id] = &WDiVertex[T]{vertex.Value, map[int]*Arc[T]{}, map[int]*Arc[T]{}}
+	}
+	for from, vertex := range g.Vertices {
+		for to, edge := range vertex.Edges {
+			directed.AddEdge(from, to, edge.Weight)
+		}
+	}
+	return directed
+}
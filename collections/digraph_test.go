@@ -0,0 +1,72 @@
+package collections
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiGraphEdges(t *testing.T) {
+	g := &DiGraph[string]{}
+	a, b, c := g.AddVertex("a"), g.AddVertex("b"), g.AddVertex("c")
+	g.AddEdge(a, b)
+	g.AddEdge(a, c)
+	g.AddEdge(c, b)
+
+	if err := g.AddEdge(a, 42); err == nil {
+		t.Fatalf("AddEdge accepted an unknown vertex")
+	}
+	if successors := g.Successors(a); !slices.Equal(successors, []int{b, c}) {
+		t.Fatalf("Successors(%v) = %v, expected %v", a, successors, []int{b, c})
+	}
+	if predecessors := g.Predecessors(b); !slices.Equal(predecessors, []int{a, c}) {
+		t.Fatalf("Predecessors(%v) = %v, expected %v", b, predecessors, []int{a, c})
+	}
+	if in, _ := g.InDegree(b); in != 2 {
+		t.Fatalf("InDegree(%v) = %v, expected 2", b, in)
+	}
+	if out, _ := g.OutDegree(b); out != 0 {
+		t.Fatalf("OutDegree(%v) = %v, expected 0", b, out)
+	}
+	if _, err := g.InDegree(42); err == nil {
+		t.Fatalf("InDegree accepted an unknown vertex")
+	}
+}
+
+func TestDiGraphReverse(t *testing.T) {
+	g := &WDiGraph[int]{}
+	a, b, c := g.AddVertex(1), g.AddVertex(2), g.AddVertex(3)
+	g.AddEdge(a, b, 1.5)
+	g.AddEdge(b, c, 2.5)
+
+	reversed := g.Reverse()
+	if successors := reversed.Successors(c); !slices.Equal(successors, []int{b}) {
+		t.Fatalf("reversed Successors(%v) = %v, expected %v", c, successors, []int{b})
+	}
+	if weight := reversed.Vertices[b].Out[a].Weight; weight != 1.5 {
+		t.Fatalf("reversed arc weight = %v, expected 1.5", weight)
+	}
+	if successors := g.Successors(a); !slices.Equal(successors, []int{b}) {
+		t.Fatalf("Reverse modified the original graph")
+	}
+
+	g.Transpose()
+	for _, id := range g.VertexIDs() {
+		if !slices.Equal(g.Successors(id), reversed.Successors(id)) ||
+			!slices.Equal(g.Predecessors(id), reversed.Predecessors(id)) {
+			t.Fatalf("Transpose and Reverse disagree on vertex %v", id)
+		}
+	}
+}
+
+func TestUndirectedGrapher(t *testing.T) {
+	var g Grapher[int] = &Graph[int]{}
+	a, b := g.AddVertex(0), g.AddVertex(0)
+	g.(*Graph[int]).AddEdge(a, b)
+
+	if g.Directed() {
+		t.Fatalf("Graph reports itself as directed")
+	}
+	if !slices.Equal(g.Successors(b), g.Predecessors(b)) {
+		t.Fatalf("undirected successors and predecessors differ")
+	}
+}
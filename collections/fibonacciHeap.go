@@ -0,0 +1,244 @@
+package collections
+
+// Lazy heap of trees whose roots form a circular list. Trees of equal degree
+// are only linked when the minimum is popped, and a priority decrease cuts the
+// node off its parent, so Push, DecreaseKey and Meld take O(1) amortized time.
+type FibonacciHeap[T, P any] struct {
+	min     *PriorityHandle[T, P]
+	count   int
+	compare func(a, b P) int
+	owner   *heapOwner
+	// Reused by consolidate
+	roots, degrees []*PriorityHandle[T, P]
+}
+
+// Siblings form circular lists through left and right, a node's child is any
+// one of its children. marked is set once a node lost a child since it was
+// linked below its parent.
+
+func NewFibonacciHeap[T, P any](compare func(a, b P) int) *FibonacciHeap[T, P] {
+	return &FibonacciHeap[T, P]{compare: compare, owner: &heapOwner{}}
+}
+
+func (h *FibonacciHeap[T, P]) Push(value T, priority P) *PriorityHandle[T, P] {
+	handle := &PriorityHandle[T, P]{Value: value, priority: priority, owner: h.owner}
+	handle.left, handle.right = handle, handle
+	h.addRoots(handle)
+	h.count++
+	return handle
+}
+
+func (h *FibonacciHeap[T, P]) Pop() (T, P, error) {
+	if h.min == nil {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	handle := h.min
+	h.popMin()
+	return handle.Value, handle.priority, nil
+}
+
+func (h *FibonacciHeap[T, P]) Peek() (T, P, error) {
+	if h.min == nil {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	return h.min.Value, h.min.priority, nil
+}
+
+// Reports whether the handle's value is still queued
+func (h *FibonacciHeap[T, P]) Contains(handle *PriorityHandle[T, P]) bool {
+	return handle.in(h.owner)
+}
+
+// Lowers the priority of a queued value, O(1) amortized.
+// Fails if the new priority would come after the current one.
+func (h *FibonacciHeap[T, P]) DecreaseKey(handle *PriorityHandle[T, P], priority P) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	if h.compare(priority, handle.priority) > 0 {
+		return ErrPriorityIncreased
+	}
+	handle.priority = priority
+	if parent := handle.parent; parent != nil && h.less(handle, parent) {
+		h.cut(handle)
+		h.cascadingCut(parent)
+	}
+	if h.less(handle, h.min) {
+		h.min = handle
+	}
+	return nil
+}
+
+// Removes a queued value, O(log(n)) amortized
+func (h *FibonacciHeap[T, P]) Remove(handle *PriorityHandle[T, P]) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	if parent := handle.parent; parent != nil {
+		h.cut(handle)
+		h.cascadingCut(parent)
+	}
+	// popped as if it came first
+	h.min = handle
+	h.popMin()
+	return nil
+}
+
+// Moves all values of other into the heap in O(1), leaving other empty.
+// Handles returned by other stay valid for this heap.
+// Both heaps must use the same ordering.
+func (h *FibonacciHeap[T, P]) Meld(other *FibonacciHeap[T, P]) {
+	if other == h {
+		return
+	}
+	other.owner.into = h.owner
+	if other.min != nil {
+		h.addRoots(other.min)
+	}
+	h.count += other.count
+	other.min, other.count, other.owner = nil, 0, &heapOwner{}
+}
+
+func (h *FibonacciHeap[T, P]) Count() int {
+	return h.count
+}
+
+func (h *FibonacciHeap[T, P]) Empty() bool {
+	return h.count == 0
+}
+
+func (h *FibonacciHeap[T, P]) less(a, b *PriorityHandle[T, P]) bool {
+	return h.compare(a.priority, b.priority) < 0
+}
+
+// Splices the circular list containing node into the root list
+func (h *FibonacciHeap[T, P]) addRoots(node *PriorityHandle[T, P]) {
+	if h.min == nil {
+		h.min = node
+		return
+	}
+	splice(h.min, node)
+	if h.less(node, h.min) {
+		h.min = node
+	}
+}
+
+// Removes the minimum, moves its children to the root list and links roots of
+// equal degree until all degrees differ
+func (h *FibonacciHeap[T, P]) popMin() {
+	z := h.min
+	if child := z.child; child != nil {
+		for node := child; ; node = node.right {
+			node.parent = nil
+			node.marked = false
+			if node.right == child {
+				break
+			}
+		}
+		splice(z, child)
+	}
+	if z.right == z {
+		h.min = nil
+	} else {
+		h.min = z.right
+		unlink(z)
+		h.consolidate()
+	}
+	z.left, z.right, z.child = nil, nil, nil
+	z.degree = 0
+	z.index = -1
+	h.count--
+}
+
+func (h *FibonacciHeap[T, P]) consolidate() {
+	roots := h.roots[:0]
+	for node := h.min; ; node = node.right {
+		roots = append(roots, node)
+		if node.right == h.min {
+			break
+		}
+	}
+	for _, x := range roots {
+		for x.degree < len(h.degrees) && h.degrees[x.degree] != nil {
+			y := h.degrees[x.degree]
+			h.degrees[x.degree] = nil
+			if h.less(y, x) {
+				x, y = y, x
+			}
+			h.link(y, x)
+		}
+		for x.degree >= len(h.degrees) {
+			h.degrees = append(h.degrees, nil)
+		}
+		h.degrees[x.degree] = x
+	}
+	clear(roots)
+	h.roots = roots
+	h.min = nil
+	for i, root := range h.degrees {
+		if root != nil && (h.min == nil || h.less(root, h.min)) {
+			h.min = root
+		}
+		h.degrees[i] = nil
+	}
+}
+
+// Moves the root y below the root x
+func (h *FibonacciHeap[T, P]) link(y, x *PriorityHandle[T, P]) {
+	unlink(y)
+	y.parent = x
+	y.marked = false
+	if x.child == nil {
+		x.child = y
+	} else {
+		splice(x.child, y)
+	}
+	x.degree++
+}
+
+// Moves a node with a parent to the root list
+func (h *FibonacciHeap[T, P]) cut(node *PriorityHandle[T, P]) {
+	parent := node.parent
+	if node.right == node {
+		parent.child = nil
+	} else {
+		if parent.child == node {
+			parent.child = node.right
+		}
+		unlink(node)
+	}
+	parent.degree--
+	node.parent = nil
+	node.marked = false
+	splice(h.min, node)
+}
+
+// Cuts the ancestors that have now lost two children, and marks the first
+// one that had not lost any
+func (h *FibonacciHeap[T, P]) cascadingCut(node *PriorityHandle[T, P]) {
+	for parent := node.parent; parent != nil; node, parent = parent, parent.parent {
+		if !node.marked {
+			node.marked = true
+			return
+		}
+		h.cut(node)
+	}
+}
+
+// Joins two circular lists
+func splice[T, P any](a, b *PriorityHandle[T, P]) {
+	aRight, bLeft := a.right, b.left
+	a.right, b.left = b, a
+	bLeft.right, aRight.left = aRight, bLeft
+}
+
+// Takes a node out of its circular list, leaving it in a list of its own
+func unlink[T, P any](node *PriorityHandle[T, P]) {
+	node.left.right = node.right
+	node.right.left = node.left
+	node.left, node.right = node, node
+}
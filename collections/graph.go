@@ -2,12 +2,47 @@ package collections
 
 import (
 	"fmt"
+	"mayerus/csgo/internal/ordered"
 )
 
+// Read access shared by every graph kind, so that algorithms can accept
+// directed and undirected graphs alike. For undirected graphs both
+// Successors and Predecessors return the neighbours of a vertex.
 type Grapher[T comparable] interface {
-	AddVertex() int
-	AddEdge()
-	//Neighbors()
+	AddVertex(value T) int
+	HasVertex(id int) bool
+	VertexCount() int
+	// Vertex IDs in ascending order
+	VertexIDs() []int
+	// IDs of the vertices reachable through a single edge, in ascending order
+	Successors(id int) []int
+	// IDs of the vertices with an edge leading to id, in ascending order
+	Predecessors(id int) []int
+	Directed() bool
+}
+
+// Graphs whose edges carry weights, unweighted graphs weigh every edge 1
+type WeightedGrapher[T comparable] interface {
+	Grapher[T]
+	Weight(idA, idB int) (float64, error)
+}
+
+// Returned when a vertex ID is not part of the graph
+type UnknownVertexError struct {
+	ID int
+}
+
+func (e *UnknownVertexError) Error() string {
+	return fmt.Sprintf("Vertex %v does not exist", e.ID)
+}
+
+// Returned when two vertices of the graph are not joined by an edge
+type UnknownEdgeError struct {
+	From, To int
+}
+
+func (e *UnknownEdgeError) Error() string {
+	return fmt.Sprintf("Edge %v-%v does not exist", e.From, e.To)
 }
 
 type Graph[T comparable] struct {
@@ -39,12 +74,18 @@ type Edge[T comparable] struct {
 }
 
 func (g *Graph[T]) AddVertex(value T) int {
+	if g.Vertices == nil {
+		g.Vertices = map[int]*Vertex[T]{}
+	}
 	g.Counter++
 	g.Vertices[g.Counter] = &Vertex[T]{value, map[int]*Vertex[T]{}}
 	return g.Counter
 }
 
 func (g *WGraph[T]) AddVertex(value T) int {
+	if g.Vertices == nil {
+		g.Vertices = map[int]*WVertex[T]{}
+	}
 	g.Counter++
 	g.Vertices[g.Counter] = &WVertex[T]{value, map[int]*Edge[T]{}}
 	return g.Counter
@@ -52,10 +93,10 @@ func (g *WGraph[T]) AddVertex(value T) int {
 
 func (g *Graph[T]) AddEdge(idA, idB int) error {
 	if _, ok := g.Vertices[idA]; !ok {
-		return fmt.Errorf("Vertex %v does not exist", idA)
+		return &UnknownVertexError{idA}
 	}
 	if _, ok := g.Vertices[idB]; !ok {
-		return fmt.Errorf("Vertex %v does not exist", idB)
+		return &UnknownVertexError{idB}
 	}
 	g.Vertices[idA].Edges[idB] = g.Vertices[idB]
 	g.Vertices[idB].Edges[idA] = g.Vertices[idA]
@@ -64,30 +105,265 @@ func (g *Graph[T]) AddEdge(idA, idB int) error {
 
 func (g *WGraph[T]) AddEdge(idA, idB int, weight float64) error {
 	if _, ok := g.Vertices[idA]; !ok {
-		return fmt.Errorf("Vertex %v does not exist", idA)
+		return &UnknownVertexError{idA}
 	}
 	if _, ok := g.Vertices[idB]; !ok {
-		return fmt.Errorf("Vertex %v does not exist", idB)
+		return &UnknownVertexError{idB}
 	}
 	g.Vertices[idA].Edges[idB] = &Edge[T]{weight, g.Vertices[idB]}
 	g.Vertices[idB].Edges[idA] = &Edge[T]{weight, g.Vertices[idA]}
 	return nil
 }
 
-//func (g *Graph[T]) Neighbors(id int) map[int]T {
-//	result := map[int]T{}
-//
-//	return g.Vertices[id].
-//	for nId, edge := range g.Vertices[id].Edges {
-//		result[nId] =
-//		result = append(result, )
-//	}
-//}
-//
-//func (g *WGraph[T]) Neighbors(id int) {
-//	result := []T{}
-//
-//	for nId, edge := range g.Vertices[id].Edges{
-//		result = append(result, )
-//	}
-//}
+func (g *Graph[T]) HasVertex(id int) bool {
+	_, ok := g.Vertices[id]
+	return ok
+}
+
+func (g *WGraph[T]) HasVertex(id int) bool {
+	_, ok := g.Vertices[id]
+	return ok
+}
+
+func (g *Graph[T]) VertexCount() int {
+	return len(g.Vertices)
+}
+
+func (g *WGraph[T]) VertexCount() int {
+	return len(g.Vertices)
+}
+
+func (g *Graph[T]) VertexIDs() []int {
+	return ordered.Keys(g.Vertices)
+}
+
+func (g *WGraph[T]) VertexIDs() []int {
+	return ordered.Keys(g.Vertices)
+}
+
+func (g *Graph[T]) Successors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.Edges)
+	}
+	return nil
+}
+
+func (g *WGraph[T]) Successors(id int) []int {
+	if vertex, ok := g.Vertices[id]; ok {
+		return ordered.Keys(vertex.Edges)
+	}
+	return nil
+}
+
+// Undirected edges lead both ways, so predecessors are the successors
+func (g *Graph[T]) Predecessors(id int) []int {
+	return g.Successors(id)
+}
+
+// Undirected edges lead both ways, so predecessors are the successors
+func (g *WGraph[T]) Predecessors(id int) []int {
+	return g.Successors(id)
+}
+
+func (g *Graph[T]) Directed() bool {
+	return false
+}
+
+func (g *WGraph[T]) Directed() bool {
+	return false
+}
+
+// Removes the vertex and all of its edges
+func (g *Graph[T]) RemoveVertex(id int) error {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return &UnknownVertexError{id}
+	}
+	for neighbour := range vertex.Edges {
+		delete(g.Vertices[neighbour].Edges, id)
+	}
+	delete(g.Vertices, id)
+	return nil
+}
+
+// Removes the vertex and all of its edges
+func (g *WGraph[T]) RemoveVertex(id int) error {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return &UnknownVertexError{id}
+	}
+	for neighbour := range vertex.Edges {
+		delete(g.Vertices[neighbour].Edges, id)
+	}
+	delete(g.Vertices, id)
+	return nil
+}
+
+func (g *Graph[T]) RemoveEdge(idA, idB int) error {
+	if err := g.checkEdge(idA, idB); err != nil {
+		return err
+	}
+	delete(g.Vertices[idA].Edges, idB)
+	delete(g.Vertices[idB].Edges, idA)
+	return nil
+}
+
+func (g *WGraph[T]) RemoveEdge(idA, idB int) error {
+	if err := g.checkEdge(idA, idB); err != nil {
+		return err
+	}
+	delete(g.Vertices[idA].Edges, idB)
+	delete(g.Vertices[idB].Edges, idA)
+	return nil
+}
+
+func (g *Graph[T]) HasEdge(idA, idB int) bool {
+	return g.checkEdge(idA, idB) == nil
+}
+
+func (g *WGraph[T]) HasEdge(idA, idB int) bool {
+	return g.checkEdge(idA, idB) == nil
+}
+
+// Unweighted edges weigh 1
+func (g *Graph[T]) Weight(idA, idB int) (float64, error) {
+	if err := g.checkEdge(idA, idB); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (g *WGraph[T]) Weight(idA, idB int) (float64, error) {
+	if err := g.checkEdge(idA, idB); err != nil {
+		return 0, err
+	}
+	return g.Vertices[idA].Edges[idB].Weight, nil
+}
+
+// Changes the weight of an existing edge (in both directions)
+func (g *WGraph[T]) SetWeight(idA, idB int, weight float64) error {
+	if err := g.checkEdge(idA, idB); err != nil {
+		return err
+	}
+	g.Vertices[idA].Edges[idB].Weight = weight
+	g.Vertices[idB].Edges[idA].Weight = weight
+	return nil
+}
+
+// Returns the IDs of the vertex's neighbours in ascending order
+func (g *Graph[T]) Neighbors(id int) ([]int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return nil, &UnknownVertexError{id}
+	}
+	return ordered.Keys(vertex.Edges), nil
+}
+
+// Returns the IDs of the vertex's neighbours in ascending order
+func (g *WGraph[T]) Neighbors(id int) ([]int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return nil, &UnknownVertexError{id}
+	}
+	return ordered.Keys(vertex.Edges), nil
+}
+
+// Returns the number of edges incident to the vertex, a self loop counts twice
+func (g *Graph[T]) Degree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	if _, loop := vertex.Edges[id]; loop {
+		return len(vertex.Edges) + 1, nil
+	}
+	return len(vertex.Edges), nil
+}
+
+// Returns the number of edges incident to the vertex, a self loop counts twice
+func (g *WGraph[T]) Degree(id int) (int, error) {
+	vertex, ok := g.Vertices[id]
+	if !ok {
+		return 0, &UnknownVertexError{id}
+	}
+	if _, loop := vertex.Edges[id]; loop {
+		return len(vertex.Edges) + 1, nil
+	}
+	return len(vertex.Edges), nil
+}
+
+func (g *Graph[T]) EdgeCount() int {
+	count := 0
+	for idA, vertex := range g.Vertices {
+		for idB := range vertex.Edges {
+			if idA <= idB {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (g *WGraph[T]) EdgeCount() int {
+	count := 0
+	for idA, vertex := range g.Vertices {
+		for idB := range vertex.Edges {
+			if idA <= idB {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Returns every edge once as {smaller ID, larger ID}, sorted
+func (g *Graph[T]) Edges() [][2]int {
+	edges := [][2]int{}
+	for _, idA := range g.VertexIDs() {
+		for _, idB := range ordered.Keys(g.Vertices[idA].Edges) {
+			if idA <= idB {
+				edges = append(edges, [2]int{idA, idB})
+			}
+		}
+	}
+	return edges
+}
+
+// Returns every edge once as {smaller ID, larger ID}, sorted
+func (g *WGraph[T]) Edges() [][2]int {
+	edges := [][2]int{}
+	for _, idA := range g.VertexIDs() {
+		for _, idB := range ordered.Keys(g.Vertices[idA].Edges) {
+			if idA <= idB {
+				edges = append(edges, [2]int{idA, idB})
+			}
+		}
+	}
+	return edges
+}
+
+func (g *Graph[T]) checkEdge(idA, idB int) error {
+	if _, ok := g.Vertices[idA]; !ok {
+		return &UnknownVertexError{idA}
+	}
+	if _, ok := g.Vertices[idB]; !ok {
+		return &UnknownVertexError{idB}
+	}
+	if _, ok := g.Vertices[idA].Edges[idB]; !ok {
+		return &UnknownEdgeError{idA, idB}
+	}
+	return nil
+}
+
+func (g *WGraph[T]) checkEdge(idA, idB int) error {
+	if _, ok := g.Vertices[idA]; !ok {
+		return &UnknownVertexError{idA}
+	}
+	if _, ok := g.Vertices[idB]; !ok {
+		return &UnknownVertexError{idB}
+	}
+	if _, ok := g.Vertices[idA].Edges[idB]; !ok {
+		return &UnknownEdgeError{idA, idB}
+	}
+	return nil
+}
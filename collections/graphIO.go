@@ -0,0 +1,594 @@
+package collections
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Graphs are read from and written to four formats:
+//
+//   - Edge lists: one edge per line as "a b" or "a b weight", separated by
+//     whitespace. A line holding a single label declares a vertex,
+//     lines starting with '#' or '%' are comments.
+//   - JSON adjacency: {"vertices": [{"id": "a", "value": ..., "adjacent": [{"id": "b", "weight": 1.5}]}]}
+//   - GraphML: nodes may carry a "value" data attribute and edges a "weight" one.
+//   - DIMACS shortest path: "p sp <nodes> <arcs>" followed by "a <from> <to> <weight>" lines.
+//
+// Readers map the external labels to the IDs returned by AddVertex, in order of
+// first appearance, and return that mapping. Writers label every vertex by its ID,
+// so reading a written graph back gives the same IDs if the original IDs are 1..n.
+// Only JSON and GraphML keep vertex values: they are JSON encoded, except for
+// string values which GraphML stores as is. Edge lists and DIMACS hold labels
+// alone, so their readers make the labels the values.
+// Edge directions are ignored, since Graph and WGraph are undirected.
+
+// Intermediate representation shared by all the formats
+type graphData[T comparable] struct {
+	labels []string
+	values map[string]T
+	edges  []dataEdge
+}
+
+type dataEdge struct {
+	a, b   string
+	weight float64
+}
+
+func newGraphData[T comparable]() *graphData[T] {
+	return &graphData[T]{values: map[string]T{}}
+}
+
+func (d *graphData[T]) addLabel(label string, value T) {
+	if _, ok := d.values[label]; ok {
+		return
+	}
+	d.labels = append(d.labels, label)
+	d.values[label] = value
+}
+
+func (d *graphData[T]) graph() (*Graph[T], map[string]int, error) {
+	g := &Graph[T]{Vertices: map[int]*Vertex[T]{}}
+	ids := map[string]int{}
+	for _, label := range d.labels {
+		ids[label] = g.AddVertex(d.values[label])
+	}
+	for _, edge := range d.edges {
+		if err := g.AddEdge(ids[edge.a], ids[edge.b]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return g, ids, nil
+}
+
+func (d *graphData[T]) wgraph() (*WGraph[T], map[string]int, error) {
+	g := &WGraph[T]{Vertices: map[int]*WVertex[T]{}}
+	ids := map[string]int{}
+	for _, label := range d.labels {
+		ids[label] = g.AddVertex(d.values[label])
+	}
+	for _, edge := range d.edges {
+		if err := g.AddEdge(ids[edge.a], ids[edge.b], edge.weight); err != nil {
+			return nil, nil, err
+		}
+	}
+	return g, ids, nil
+}
+
+func exportGraph[T comparable](g *Graph[T]) *graphData[T] {
+	d := newGraphData[T]()
+	for _, id := range g.VertexIDs() {
+		d.addLabel(strconv.Itoa(id), g.Vertices[id].Value)
+	}
+	for _, edge := range g.Edges() {
+		d.edges = append(d.edges, dataEdge{strconv.Itoa(edge[0]), strconv.Itoa(edge[1]), 1})
+	}
+	return d
+}
+
+func exportWGraph[T comparable](g *WGraph[T]) *graphData[T] {
+	d := newGraphData[T]()
+	for _, id := range g.VertexIDs() {
+		d.addLabel(strconv.Itoa(id), g.Vertices[id].Value)
+	}
+	for _, edge := range g.Edges() {
+		weight := g.Vertices[edge[0]].Edges[edge[1]].Weight
+		d.edges = append(d.edges, dataEdge{strconv.Itoa(edge[0]), strconv.Itoa(edge[1]), weight})
+	}
+	return d
+}
+
+// Reads an edge list, the label of every vertex becomes its value
+func ReadEdgeList(r io.Reader) (*Graph[string], map[string]int, error) {
+	d, err := parseEdgeList(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.graph()
+}
+
+// Reads a weighted edge list, edges without a weight weigh 1.
+// The label of every vertex becomes its value.
+func ReadWeightedEdgeList(r io.Reader) (*WGraph[string], map[string]int, error) {
+	d, err := parseEdgeList(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.wgraph()
+}
+
+// Writes the edges labelled by vertex ID. Values are lost,
+// WriteJSON and WriteGraphML keep them.
+func (g *Graph[T]) WriteEdgeList(w io.Writer) error {
+	return writeEdgeList(w, exportGraph(g), false)
+}
+
+// Writes the weighted edges labelled by vertex ID. Values are lost,
+// WriteJSON and WriteGraphML keep them.
+func (g *WGraph[T]) WriteEdgeList(w io.Writer) error {
+	return writeEdgeList(w, exportWGraph(g), true)
+}
+
+func parseEdgeList(r io.Reader) (*graphData[string], error) {
+	d := newGraphData[string]()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "%") {
+			continue
+		}
+		for _, label := range fields[:min(len(fields), 2)] {
+			d.addLabel(label, label)
+		}
+		switch len(fields) {
+		case 1:
+		case 2:
+			d.edges = append(d.edges, dataEdge{fields[0], fields[1], 1})
+		case 3:
+			weight, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("Line %v: invalid weight %q", line, fields[2])
+			}
+			d.edges = append(d.edges, dataEdge{fields[0], fields[1], weight})
+		default:
+			return nil, fmt.Errorf("Line %v: expected at most 3 fields, got %v", line, len(fields))
+		}
+	}
+	return d, scanner.Err()
+}
+
+// Writes every edge once both its ends are declared, so that the labels first
+// appear in the order of the vertices and reading them back gives the same IDs.
+// A vertex whose edges all lead to later vertices gets a line of its own.
+func writeEdgeList[T comparable](w io.Writer, d *graphData[T], weighted bool) error {
+	buffer := bufio.NewWriter(w)
+	position := map[string]int{}
+	for i, label := range d.labels {
+		position[label] = i
+	}
+	// edges by the position of their later end
+	closing := make([][]dataEdge, len(d.labels))
+	for _, edge := range d.edges {
+		last := max(position[edge.a], position[edge.b])
+		closing[last] = append(closing[last], edge)
+	}
+	for i, label := range d.labels {
+		if len(closing[i]) == 0 {
+			fmt.Fprintln(buffer, label)
+		}
+		for _, edge := range closing[i] {
+			if weighted {
+				fmt.Fprintf(buffer, "%v %v %v\n", edge.a, edge.b, strconv.FormatFloat(edge.weight, 'g', -1, 64))
+				continue
+			}
+			fmt.Fprintf(buffer, "%v %v\n", edge.a, edge.b)
+		}
+	}
+	return buffer.Flush()
+}
+
+type jsonGraph[T any] struct {
+	Vertices []jsonVertex[T] `json:"vertices"`
+}
+
+type jsonVertex[T any] struct {
+	ID       string         `json:"id"`
+	Value    T              `json:"value"`
+	Adjacent []jsonAdjacent `json:"adjacent"`
+}
+
+type jsonAdjacent struct {
+	ID     string   `json:"id"`
+	Weight *float64 `json:"weight,omitempty"`
+}
+
+// Reads a JSON adjacency document, edge weights are ignored
+func ReadJSON[T comparable](r io.Reader) (*Graph[T], map[string]int, error) {
+	d, err := parseJSON[T](r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.graph()
+}
+
+// Reads a JSON adjacency document, edges without a weight weigh 1
+func ReadWeightedJSON[T comparable](r io.Reader) (*WGraph[T], map[string]int, error) {
+	d, err := parseJSON[T](r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.wgraph()
+}
+
+func (g *Graph[T]) WriteJSON(w io.Writer) error {
+	return writeJSON(w, exportGraph(g), false)
+}
+
+func (g *WGraph[T]) WriteJSON(w io.Writer) error {
+	return writeJSON(w, exportWGraph(g), true)
+}
+
+func parseJSON[T comparable](r io.Reader) (*graphData[T], error) {
+	document := jsonGraph[T]{}
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+	d := newGraphData[T]()
+	for _, vertex := range document.Vertices {
+		if _, ok := d.values[vertex.ID]; ok {
+			return nil, fmt.Errorf("Vertex %q is declared twice", vertex.ID)
+		}
+		d.addLabel(vertex.ID, vertex.Value)
+	}
+	for _, vertex := range document.Vertices {
+		for _, adjacent := range vertex.Adjacent {
+			if _, ok := d.values[adjacent.ID]; !ok {
+				return nil, fmt.Errorf("Vertex %q is adjacent to undeclared vertex %q", vertex.ID, adjacent.ID)
+			}
+			weight := 1.0
+			if adjacent.Weight != nil {
+				weight = *adjacent.Weight
+			}
+			d.edges = append(d.edges, dataEdge{vertex.ID, adjacent.ID, weight})
+		}
+	}
+	return d, nil
+}
+
+func writeJSON[T comparable](w io.Writer, d *graphData[T], weighted bool) error {
+	document := jsonGraph[T]{Vertices: make([]jsonVertex[T], len(d.labels))}
+	index := map[string]int{}
+	for i, label := range d.labels {
+		document.Vertices[i] = jsonVertex[T]{label, d.values[label], []jsonAdjacent{}}
+		index[label] = i
+	}
+	for _, edge := range d.edges {
+		var weight *float64
+		if weighted {
+			weight = &edge.weight
+		}
+		a, b := index[edge.a], index[edge.b]
+		document.Vertices[a].Adjacent = append(document.Vertices[a].Adjacent, jsonAdjacent{edge.b, weight})
+		if a != b {
+			document.Vertices[b].Adjacent = append(document.Vertices[b].Adjacent, jsonAdjacent{edge.a, weight})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Reads a GraphML document, edge weights are ignored
+func ReadGraphML[T comparable](r io.Reader) (*Graph[T], map[string]int, error) {
+	d, err := parseGraphML[T](r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.graph()
+}
+
+// Reads a GraphML document, edges without a weight weigh 1
+func ReadWeightedGraphML[T comparable](r io.Reader) (*WGraph[T], map[string]int, error) {
+	d, err := parseGraphML[T](r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.wgraph()
+}
+
+func (g *Graph[T]) WriteGraphML(w io.Writer) error {
+	return writeGraphML(w, exportGraph(g), false)
+}
+
+func (g *WGraph[T]) WriteGraphML(w io.Writer) error {
+	return writeGraphML(w, exportWGraph(g), true)
+}
+
+func parseGraphML[T comparable](r io.Reader) (*graphData[T], error) {
+	document := graphML{}
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+	// data elements refer to keys by ID, the attribute names identify them
+	names := map[string]string{}
+	for _, key := range document.Keys {
+		names[key.ID] = key.Name
+	}
+	attribute := func(data []graphMLData, name string) (string, bool) {
+		for _, d := range data {
+			if d.Key == name || names[d.Key] == name {
+				return d.Value, true
+			}
+		}
+		return "", false
+	}
+
+	d := newGraphData[T]()
+	for _, node := range document.Graph.Nodes {
+		if _, ok := d.values[node.ID]; ok {
+			return nil, fmt.Errorf("Node %q is declared twice", node.ID)
+		}
+		var value T
+		if text, ok := attribute(node.Data, "value"); ok {
+			if err := decodeText(text, &value); err != nil {
+				return nil, fmt.Errorf("Node %q: %w", node.ID, err)
+			}
+		}
+		d.addLabel(node.ID, value)
+	}
+	for _, edge := range document.Graph.Edges {
+		for _, label := range []string{edge.Source, edge.Target} {
+			if _, ok := d.values[label]; !ok {
+				return nil, fmt.Errorf("Edge refers to undeclared node %q", label)
+			}
+		}
+		weight := 1.0
+		if text, ok := attribute(edge.Data, "weight"); ok {
+			var err error
+			if weight, err = strconv.ParseFloat(strings.TrimSpace(text), 64); err != nil {
+				return nil, fmt.Errorf("Edge %v-%v: invalid weight %q", edge.Source, edge.Target, text)
+			}
+		}
+		d.edges = append(d.edges, dataEdge{edge.Source, edge.Target, weight})
+	}
+	return d, nil
+}
+
+func writeGraphML[T comparable](w io.Writer, d *graphData[T], weighted bool) error {
+	document := graphML{
+		Xmlns: graphMLNamespace,
+		Keys:  []graphMLKey{graphMLValueKey[T]()},
+		Graph: graphMLGraph{ID: "G", EdgeDefault: "undirected"},
+	}
+	if weighted {
+		document.Keys = append(document.Keys, graphMLKey{"weight", "edge", "weight", "double", ""})
+	}
+	for _, label := range d.labels {
+		text, err := encodeText(d.values[label])
+		if err != nil {
+			return err
+		}
+		document.Graph.Nodes = append(document.Graph.Nodes, graphMLNode{label, []graphMLData{{"value", text}}})
+	}
+	for _, edge := range d.edges {
+		data := []graphMLData{}
+		if weighted {
+			data = append(data, graphMLData{"weight", strconv.FormatFloat(edge.weight, 'g', -1, 64)})
+		}
+		document.Graph.Edges = append(document.Graph.Edges, graphMLEdge{edge.a, edge.b, data})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Key of the node values. Booleans and numbers are declared as such, since
+// their JSON encoding is also their GraphML one. Other values are declared as
+// strings holding JSON, except for plain strings which are stored as is.
+func graphMLValueKey[T any]() graphMLKey {
+	key := graphMLKey{"value", "node", "value", "string", ""}
+	var value T
+	switch reflect.TypeOf(&value).Elem().Kind() {
+	case reflect.String:
+		if _, ok := any(value).(string); !ok {
+			key.Desc = "JSON encoded"
+		}
+	case reflect.Bool:
+		key.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		key.Type = "long"
+	case reflect.Float32, reflect.Float64:
+		key.Type = "double"
+	default:
+		key.Desc = "JSON encoded"
+	}
+	return key
+}
+
+// Strings are stored as is, any other value as JSON
+func encodeText[T any](value T) (string, error) {
+	if text, ok := any(value).(string); ok {
+		return text, nil
+	}
+	encoded, err := json.Marshal(value)
+	return string(encoded), err
+}
+
+func decodeText[T any](text string, value *T) error {
+	if target, ok := any(value).(*string); ok {
+		*target = text
+		return nil
+	}
+	return json.Unmarshal([]byte(text), value)
+}
+
+// Reads a DIMACS shortest path file, arc weights are ignored.
+// DIMACS node n gets ID n, which is also its value.
+func ReadDIMACS(r io.Reader) (*Graph[int], error) {
+	d, err := parseDIMACS(r)
+	if err != nil {
+		return nil, err
+	}
+	g, _, err := d.graph()
+	return g, err
+}
+
+// Reads a DIMACS shortest path file.
+// DIMACS node n gets ID n, which is also its value.
+// Arcs in both directions between the same nodes make up a single edge,
+// with the weight of the arc listed last.
+func ReadWeightedDIMACS(r io.Reader) (*WGraph[int], error) {
+	d, err := parseDIMACS(r)
+	if err != nil {
+		return nil, err
+	}
+	g, _, err := d.wgraph()
+	return g, err
+}
+
+// Writes every edge as a pair of opposite arcs of weight 1.
+// DIMACS numbers the nodes 1..n, so the vertices are renumbered in ascending ID order.
+// Values are lost, WriteJSON and WriteGraphML keep them.
+func (g *Graph[T]) WriteDIMACS(w io.Writer) error {
+	return writeDIMACS(w, exportGraph(g))
+}
+
+// Writes every edge as a pair of opposite arcs.
+// DIMACS numbers the nodes 1..n, so the vertices are renumbered in ascending ID order.
+// Values are lost, WriteJSON and WriteGraphML keep them.
+func (g *WGraph[T]) WriteDIMACS(w io.Writer) error {
+	return writeDIMACS(w, exportWGraph(g))
+}
+
+func parseDIMACS(r io.Reader) (*graphData[int], error) {
+	d := newGraphData[int]()
+	nodes := -1
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		switch fields[0] {
+		case "p":
+			if nodes != -1 {
+				return nil, fmt.Errorf("Line %v: duplicate problem line", line)
+			}
+			if len(fields) != 4 || fields[1] != "sp" {
+				return nil, fmt.Errorf("Line %v: expected \"p sp <nodes> <arcs>\"", line)
+			}
+			var err error
+			if nodes, err = strconv.Atoi(fields[2]); err != nil || nodes < 0 {
+				return nil, fmt.Errorf("Line %v: invalid node count %q", line, fields[2])
+			}
+			for n := 1; n <= nodes; n++ {
+				d.addLabel(strconv.Itoa(n), n)
+			}
+		case "a":
+			if nodes == -1 {
+				return nil, fmt.Errorf("Line %v: arc before the problem line", line)
+			}
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("Line %v: expected \"a <from> <to> <weight>\"", line)
+			}
+			for _, field := range fields[1:3] {
+				if n, err := strconv.Atoi(field); err != nil || n < 1 || n > nodes {
+					return nil, fmt.Errorf("Line %v: invalid node %q", line, field)
+				}
+			}
+			weight, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("Line %v: invalid weight %q", line, fields[3])
+			}
+			d.edges = append(d.edges, dataEdge{fields[1], fields[2], weight})
+		default:
+			return nil, fmt.Errorf("Line %v: unknown line type %q", line, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if nodes == -1 {
+		return nil, fmt.Errorf("Missing problem line")
+	}
+	return d, nil
+}
+
+func writeDIMACS[T comparable](w io.Writer, d *graphData[T]) error {
+	buffer := bufio.NewWriter(w)
+	node := map[string]int{}
+	for i, label := range d.labels {
+		node[label] = i + 1
+	}
+	arcs := 0
+	for _, edge := range d.edges {
+		arcs += 2
+		if edge.a == edge.b {
+			arcs--
+		}
+	}
+
+	fmt.Fprintf(buffer, "p sp %v %v\n", len(d.labels), arcs)
+	for _, edge := range d.edges {
+		weight := strconv.FormatFloat(edge.weight, 'g', -1, 64)
+		fmt.Fprintf(buffer, "a %v %v %v\n", node[edge.a], node[edge.b], weight)
+		if edge.a != edge.b {
+			fmt.Fprintf(buffer, "a %v %v %v\n", node[edge.b], node[edge.a], weight)
+		}
+	}
+	return buffer.Flush()
+}
@@ -0,0 +1,208 @@
+package collections
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type point struct {
+	X, Y int
+}
+
+func sampleWGraph() *WGraph[point] {
+	g := &WGraph[point]{}
+	for i := 0; i < 5; i++ {
+		g.AddVertex(point{i, i * i})
+	}
+	g.AddEdge(1, 2, 1.5)
+	g.AddEdge(2, 3, 2)
+	g.AddEdge(3, 1, 0.25)
+	g.AddEdge(4, 4, 7)
+	return g
+}
+
+func checkSameWGraph[T comparable](original, read *WGraph[T], weights bool, t *testing.T) {
+	if !slices.Equal(original.VertexIDs(), read.VertexIDs()) {
+		t.Fatalf("vertex IDs %v, expected %v", read.VertexIDs(), original.VertexIDs())
+	}
+	if !slices.Equal(original.Edges(), read.Edges()) {
+		t.Fatalf("edges %v, expected %v", read.Edges(), original.Edges())
+	}
+	for _, id := range original.VertexIDs() {
+		if original.Vertices[id].Value != read.Vertices[id].Value {
+			t.Fatalf("vertex %v value %v, expected %v", id, read.Vertices[id].Value, original.Vertices[id].Value)
+		}
+	}
+	if !weights {
+		return
+	}
+	for _, edge := range original.Edges() {
+		expected, _ := original.Weight(edge[0], edge[1])
+		if weight, _ := read.Weight(edge[0], edge[1]); weight != expected {
+			t.Fatalf("edge %v weight %v, expected %v", edge, weight, expected)
+		}
+	}
+}
+
+func TestGraphRoundTrips(t *testing.T) {
+	g := sampleWGraph()
+
+	buffer := &bytes.Buffer{}
+	if err := g.WriteJSON(buffer); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, _, err := ReadWeightedJSON[point](buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSameWGraph(g, fromJSON, true, t)
+
+	buffer.Reset()
+	if err := g.WriteGraphML(buffer); err != nil {
+		t.Fatal(err)
+	}
+	fromGraphML, _, err := ReadWeightedGraphML[point](buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSameWGraph(g, fromGraphML, true, t)
+	for _, key := range []struct {
+		key, expected graphMLKey
+	}{
+		{graphMLValueKey[string](), graphMLKey{"value", "node", "value", "string", ""}},
+		{graphMLValueKey[uint8](), graphMLKey{"value", "node", "value", "long", ""}},
+		{graphMLValueKey[float64](), graphMLKey{"value", "node", "value", "double", ""}},
+		{graphMLValueKey[point](), graphMLKey{"value", "node", "value", "string", "JSON encoded"}},
+	} {
+		if key.key != key.expected {
+			t.Fatalf("value key %+v, expected %+v", key.key, key.expected)
+		}
+	}
+
+	buffer.Reset()
+	if err := g.WriteDIMACS(buffer); err != nil {
+		t.Fatal(err)
+	}
+	fromDIMACS, err := ReadWeightedDIMACS(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(g.Edges(), fromDIMACS.Edges()) {
+		t.Fatalf("DIMACS edges %v, expected %v", fromDIMACS.Edges(), g.Edges())
+	}
+
+	buffer.Reset()
+	g.AddVertex(point{-1, -1})
+	if err := g.WriteEdgeList(buffer); err != nil {
+		t.Fatal(err)
+	}
+	fromEdgeList, labels, err := ReadWeightedEdgeList(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromEdgeList.VertexCount() != 6 || fromEdgeList.EdgeCount() != 4 {
+		t.Fatalf("edge list graph has %v vertices and %v edges", fromEdgeList.VertexCount(), fromEdgeList.EdgeCount())
+	}
+	if weight, _ := fromEdgeList.Weight(labels["1"], labels["3"]); weight != 0.25 {
+		t.Fatalf("edge list weight %v, expected 0.25", weight)
+	}
+}
+
+func TestEdgeListIDs(t *testing.T) {
+	g := &Graph[int]{}
+	for i := 0; i < 5; i++ {
+		g.AddVertex(i)
+	}
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 2)
+	buffer := &bytes.Buffer{}
+	if err := g.WriteEdgeList(buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, labels, err := ReadEdgeList(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range g.VertexIDs() {
+		if labels[strconv.Itoa(id)] != id {
+			t.Fatalf("vertex %v read back as %v", id, labels[strconv.Itoa(id)])
+		}
+	}
+	if !slices.Equal(read.Edges(), g.Edges()) {
+		t.Fatalf("edges %v, expected %v", read.Edges(), g.Edges())
+	}
+}
+
+func TestReadEdgeList(t *testing.T) {
+	input := `# routers
+core edge1
+core edge2 ignored-weight-is-invalid
+`
+	if _, _, err := ReadWeightedEdgeList(strings.NewReader(input)); err == nil {
+		t.Fatalf("invalid weight accepted")
+	}
+
+	input = "% comment\ncore edge1\ncore edge2\nspare\n"
+	g, labels, err := ReadEdgeList(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]int{"core": 1, "edge1": 2, "edge2": 3, "spare": 4}; len(labels) != 4 || labels["spare"] != expected["spare"] || labels["edge2"] != expected["edge2"] {
+		t.Fatalf("labels %v, expected %v", labels, expected)
+	}
+	if neighbours, _ := g.Neighbors(labels["core"]); !slices.Equal(neighbours, []int{2, 3}) {
+		t.Fatalf("core neighbours %v, expected [2 3]", neighbours)
+	}
+	if g.Vertices[labels["edge1"]].Value != "edge1" {
+		t.Fatalf("vertex value %q, expected the label", g.Vertices[labels["edge1"]].Value)
+	}
+}
+
+func TestReadForeignGraphML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="value" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="G" edgedefault="directed">
+    <node id="n0"><data key="d0">alpha</data></node>
+    <node id="n1"/>
+    <edge source="n0" target="n1"><data key="d1">3.5</data></edge>
+  </graph>
+</graphml>`
+	g, labels, err := ReadWeightedGraphML[string](strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Vertices[labels["n0"]].Value != "alpha" || g.Vertices[labels["n1"]].Value != "" {
+		t.Fatalf("unexpected values %q, %q", g.Vertices[labels["n0"]].Value, g.Vertices[labels["n1"]].Value)
+	}
+	if weight, _ := g.Weight(labels["n1"], labels["n0"]); weight != 3.5 {
+		t.Fatalf("weight %v, expected 3.5", weight)
+	}
+
+	broken := strings.Replace(input, `target="n1"`, `target="n2"`, 1)
+	if _, _, err := ReadGraphML[string](strings.NewReader(broken)); err == nil {
+		t.Fatalf("edge to an undeclared node accepted")
+	}
+}
+
+func TestReadDIMACS(t *testing.T) {
+	input := "c sample\np sp 3 2\na 1 2 7\na 2 3 4\n"
+	g, err := ReadDIMACS(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(g.Edges(), [][2]int{{1, 2}, {2, 3}}) || g.Vertices[3].Value != 3 {
+		t.Fatalf("unexpected DIMACS graph %v", g.Edges())
+	}
+	if _, err := ReadDIMACS(strings.NewReader("p sp 2 1\na 1 3 1\n")); err == nil {
+		t.Fatalf("arc to a node out of range accepted")
+	}
+	if _, err := ReadDIMACS(strings.NewReader("a 1 2 1\n")); err == nil {
+		t.Fatalf("arc before the problem line accepted")
+	}
+}
@@ -0,0 +1,77 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGraphRemoval(t *testing.T) {
+	g := &Graph[string]{}
+	a, b, c, d := g.AddVertex("a"), g.AddVertex("b"), g.AddVertex("c"), g.AddVertex("d")
+	g.AddEdge(a, b)
+	g.AddEdge(a, c)
+	g.AddEdge(b, c)
+	g.AddEdge(c, d)
+	g.AddEdge(d, d)
+
+	if count := g.EdgeCount(); count != 5 {
+		t.Fatalf("EdgeCount() = %v, expected 5", count)
+	}
+	if degree, _ := g.Degree(d); degree != 3 {
+		t.Fatalf("Degree(%v) = %v, expected 3 (self loop counts twice)", d, degree)
+	}
+
+	if err := g.RemoveEdge(a, c); err != nil {
+		t.Fatal(err)
+	}
+	if g.HasEdge(a, c) || g.HasEdge(c, a) {
+		t.Fatalf("edge %v-%v survived removal", a, c)
+	}
+	var edgeErr *UnknownEdgeError
+	if err := g.RemoveEdge(a, c); !errors.As(err, &edgeErr) {
+		t.Fatalf("expected UnknownEdgeError, got %v", err)
+	}
+
+	if err := g.RemoveVertex(c); err != nil {
+		t.Fatal(err)
+	}
+	if neighbours, _ := g.Neighbors(b); !slices.Equal(neighbours, []int{a}) {
+		t.Fatalf("Neighbors(%v) = %v, expected %v", b, neighbours, []int{a})
+	}
+	if edges := g.Edges(); !slices.Equal(edges, [][2]int{{a, b}, {d, d}}) {
+		t.Fatalf("Edges() = %v, expected %v", edges, [][2]int{{a, b}, {d, d}})
+	}
+	if g.VertexCount() != 3 {
+		t.Fatalf("VertexCount() = %v, expected 3", g.VertexCount())
+	}
+
+	var vertexErr *UnknownVertexError
+	if _, err := g.Neighbors(c); !errors.As(err, &vertexErr) || vertexErr.ID != c {
+		t.Fatalf("expected UnknownVertexError for %v, got %v", c, err)
+	}
+	if err := g.AddEdge(a, c); !errors.As(err, &vertexErr) {
+		t.Fatalf("expected UnknownVertexError, got %v", err)
+	}
+}
+
+func TestWGraphWeights(t *testing.T) {
+	g := &WGraph[int]{}
+	a, b := g.AddVertex(1), g.AddVertex(2)
+	g.AddEdge(a, b, 2.5)
+
+	if err := g.SetWeight(b, a, 4); err != nil {
+		t.Fatal(err)
+	}
+	if weight, _ := g.Weight(a, b); weight != 4 {
+		t.Fatalf("Weight(%v, %v) = %v, expected 4", a, b, weight)
+	}
+	if _, err := g.Weight(a, a); err == nil {
+		t.Fatalf("Weight of a missing edge returned no error")
+	}
+
+	g.RemoveVertex(a)
+	if degree, _ := g.Degree(b); degree != 0 || g.EdgeCount() != 0 {
+		t.Fatalf("edges of a removed vertex remain: %v", g.Edges())
+	}
+}
@@ -0,0 +1,272 @@
+package collections
+
+import (
+	"errors"
+	"slices"
+)
+
+var (
+	ErrNothingToUndo   = errors.New("Nothing to undo")
+	ErrNothingToRedo   = errors.New("Nothing to redo")
+	ErrTransactionOpen = errors.New("Transaction is open")
+	ErrNoTransaction   = errors.New("No transaction is open")
+)
+
+// Reversible change of a state of type S. Pointer states may be changed in
+// place and returned. A command that fails must leave the state unchanged.
+type Command[S any] interface {
+	Do(state S) (S, error)
+	Undo(state S) (S, error)
+}
+
+// Command made of two functions
+type FuncCommand[S any] struct {
+	DoFunc, UndoFunc func(state S) (S, error)
+}
+
+func (c FuncCommand[S]) Do(state S) (S, error) {
+	return c.DoFunc(state)
+}
+
+func (c FuncCommand[S]) Undo(state S) (S, error) {
+	return c.UndoFunc(state)
+}
+
+// Undo and redo history of a state. Commands done between Begin and Commit
+// form a transaction that is undone and redone as a whole. At most limit
+// commands or transactions can be undone, the oldest ones are forgotten first.
+type History[S any] struct {
+	state S
+	// Entries to undo and redo, the next one on top
+	undo, redo Stack[*historyEntry[S]]
+	limit      int
+	// Number of entries at the bottom of undo that went beyond the limit. They
+	// are removed in batches, so that recording an entry takes O(1) amortized.
+	forgotten int
+	// Open transaction and the number of Begin calls it awaits Commit for
+	transaction *historyEntry[S]
+	depth       int
+}
+
+// Commands undone and redone together, in the order they were done
+type historyEntry[S any] struct {
+	commands []Command[S]
+}
+
+// Copy of a history, for instance to persist it with the commands encoded by
+// the caller. Entries are lists of commands in the order they were done.
+type HistorySnapshot[S any] struct {
+	State S
+	// Entries that can be undone, the next one last
+	Undo [][]Command[S]
+	// Entries that can be redone, the next one last
+	Redo  [][]Command[S]
+	Limit int
+}
+
+// History starting at the given state, unbounded if limit is 0.
+// Panics if limit is negative.
+func NewHistory[S any](state S, limit int) *History[S] {
+	if limit < 0 {
+		panic("History limit is negative")
+	}
+	return &History[S]{state: state, limit: limit}
+}
+
+func (h *History[S]) State() S {
+	return h.state
+}
+
+// Applies the command and records it, or adds it to the open transaction.
+// Forgets the undone entries. A failed command is not recorded.
+func (h *History[S]) Do(command Command[S]) error {
+	state, err := command.Do(h.state)
+	if err != nil {
+		return err
+	}
+	h.state = state
+	clear(h.redo)
+	h.redo = h.redo[:0]
+	if h.transaction != nil {
+		h.transaction.commands = append(h.transaction.commands, command)
+		return nil
+	}
+	h.record(&historyEntry[S]{[]Command[S]{command}})
+	return nil
+}
+
+// Reverts the last command or transaction. If a command fails, the entry's
+// commands already undone are done again, see undoCommands.
+func (h *History[S]) Undo() error {
+	if h.transaction != nil {
+		return ErrTransactionOpen
+	}
+	if h.UndoCount() == 0 {
+		return ErrNothingToUndo
+	}
+	entry, _ := h.undo.Peek()
+	if err := h.undoCommands(entry.commands); err != nil {
+		return err
+	}
+	h.undo.Pop()
+	h.redo.Push(entry)
+	return nil
+}
+
+// Applies the last undone command or transaction again. If a command fails,
+// the entry's commands already done are undone, see undoCommands.
+func (h *History[S]) Redo() error {
+	if h.transaction != nil {
+		return ErrTransactionOpen
+	}
+	entry, err := h.redo.Peek()
+	if err != nil {
+		return ErrNothingToRedo
+	}
+	for i, command := range entry.commands {
+		state, err := command.Do(h.state)
+		if err != nil {
+			// back to where the redo started
+			if undoErr := h.undoCommands(entry.commands[:i]); undoErr != nil {
+				return errors.Join(err, undoErr)
+			}
+			return err
+		}
+		h.state = state
+	}
+	h.redo.Pop()
+	h.record(entry)
+	return nil
+}
+
+// Opens a transaction. Nested calls join the open transaction,
+// which ends with the outermost Commit.
+func (h *History[S]) Begin() {
+	if h.transaction == nil {
+		h.transaction = &historyEntry[S]{}
+	}
+	h.depth++
+}
+
+// Closes the innermost Begin. The outermost one records the transaction's
+// commands as a single entry, if there are any.
+func (h *History[S]) Commit() error {
+	if h.transaction == nil {
+		return ErrNoTransaction
+	}
+	h.depth--
+	if h.depth > 0 {
+		return nil
+	}
+	entry := h.transaction
+	h.transaction = nil
+	if len(entry.commands) > 0 {
+		h.record(entry)
+	}
+	return nil
+}
+
+// Undoes the commands of the open transaction and closes it, however many
+// Begin calls are open
+func (h *History[S]) Rollback() error {
+	if h.transaction == nil {
+		return ErrNoTransaction
+	}
+	if err := h.undoCommands(h.transaction.commands); err != nil {
+		return err
+	}
+	h.transaction, h.depth = nil, 0
+	return nil
+}
+
+func (h *History[S]) CanUndo() bool {
+	return h.transaction == nil && h.UndoCount() > 0
+}
+
+func (h *History[S]) CanRedo() bool {
+	return h.transaction == nil && !h.redo.Empty()
+}
+
+// Number of entries that can be undone
+func (h *History[S]) UndoCount() int {
+	return h.undo.Count() - h.forgotten
+}
+
+// Number of entries that can be redone
+func (h *History[S]) RedoCount() int {
+	return h.redo.Count()
+}
+
+// Copies the state and the entries. Fails while a transaction is open.
+func (h *History[S]) Snapshot() (HistorySnapshot[S], error) {
+	if h.transaction != nil {
+		return HistorySnapshot[S]{}, ErrTransactionOpen
+	}
+	snapshot := HistorySnapshot[S]{State: h.state, Undo: [][]Command[S]{}, Redo: [][]Command[S]{}, Limit: h.limit}
+	for _, entry := range h.undo[h.forgotten:] {
+		snapshot.Undo = append(snapshot.Undo, slices.Clone(entry.commands))
+	}
+	for _, entry := range h.redo {
+		snapshot.Redo = append(snapshot.Redo, slices.Clone(entry.commands))
+	}
+	return snapshot, nil
+}
+
+// Replaces the state and the entries by those of the snapshot, keeping the
+// newest entries if the snapshot holds more than its limit allows.
+// Fails while a transaction is open.
+func (h *History[S]) Restore(snapshot HistorySnapshot[S]) error {
+	if h.transaction != nil {
+		return ErrTransactionOpen
+	}
+	if snapshot.Limit < 0 {
+		return errors.New("History limit is negative")
+	}
+	h.state, h.limit = snapshot.State, snapshot.Limit
+	h.undo, h.redo, h.forgotten = Stack[*historyEntry[S]]{}, Stack[*historyEntry[S]]{}, 0
+	for _, commands := range snapshot.Undo {
+		h.record(&historyEntry[S]{slices.Clone(commands)})
+	}
+	for _, commands := range snapshot.Redo {
+		h.redo.Push(&historyEntry[S]{slices.Clone(commands)})
+	}
+	return nil
+}
+
+// Pushes an entry to undo, forgetting the oldest one beyond the limit.
+// The forgotten entries are removed once there are as many as the limit.
+func (h *History[S]) record(entry *historyEntry[S]) {
+	h.undo.Push(entry)
+	if h.limit == 0 || h.UndoCount() <= h.limit {
+		return
+	}
+	h.undo[h.forgotten] = nil
+	h.forgotten++
+	if h.forgotten >= h.limit {
+		kept := copy(h.undo, h.undo[h.forgotten:])
+		clear(h.undo[kept:])
+		h.undo, h.forgotten = h.undo[:kept], 0
+	}
+}
+
+// Undoes the commands in reverse order. If one fails, the ones already undone
+// are done again, so that the state is back to where it was. Should doing one
+// of them fail too, both errors are returned and the state is left part way,
+// matching neither the undo nor the redo entries.
+func (h *History[S]) undoCommands(commands []Command[S]) error {
+	for i := len(commands) - 1; i >= 0; i-- {
+		state, err := commands[i].Undo(h.state)
+		if err != nil {
+			for _, command := range commands[i+1:] {
+				state, redoErr := command.Do(h.state)
+				if redoErr != nil {
+					return errors.Join(err, redoErr)
+				}
+				h.state = state
+			}
+			return err
+		}
+		h.state = state
+	}
+	return nil
+}
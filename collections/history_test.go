@@ -0,0 +1,270 @@
+package collections
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Appends text to the end of a string
+type appendText struct {
+	Text string
+}
+
+func (c appendText) Do(state string) (string, error) {
+	if strings.Contains(c.Text, "!") {
+		return state, errors.New("text is rejected")
+	}
+	return state + c.Text, nil
+}
+
+func (c appendText) Undo(state string) (string, error) {
+	if !strings.HasSuffix(state, c.Text) {
+		return state, errors.New("text is missing")
+	}
+	return strings.TrimSuffix(state, c.Text), nil
+}
+
+// Runs the steps, checking the state after each one
+func checkHistory(h *History[string], steps []func() error, states []string, t *testing.T) {
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %v: %v", i, err)
+		}
+		if h.State() != states[i] {
+			t.Fatalf("step %v: state %q, expected %q", i, h.State(), states[i])
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory("", 0)
+	if h.Undo() != ErrNothingToUndo || h.Redo() != ErrNothingToRedo {
+		t.Fatalf("undo or redo of an empty history")
+	}
+	do := func(text string) func() error {
+		return func() error { return h.Do(appendText{text}) }
+	}
+	checkHistory(h, []func() error{
+		do("a"), do("b"), do("c"),
+		h.Undo, h.Undo, h.Redo, h.Undo, h.Undo,
+		h.Redo, do("d"),
+	}, []string{
+		"a", "ab", "abc",
+		"ab", "a", "ab", "a", "",
+		"a", "ad",
+	}, t)
+	// a new command forgets the undone ones
+	if h.CanRedo() || h.Redo() != ErrNothingToRedo || h.UndoCount() != 2 {
+		t.Fatalf("redo after a new command")
+	}
+	if err := h.Do(appendText{"!"}); err == nil || h.State() != "ad" || h.UndoCount() != 2 {
+		t.Fatalf("failed command recorded: %v", err)
+	}
+}
+
+func TestHistoryTransactions(t *testing.T) {
+	h := NewHistory("", 0)
+	h.Do(appendText{"a"})
+	h.Begin()
+	h.Do(appendText{"b"})
+	h.Begin()
+	h.Do(appendText{"c"})
+	if err := h.Commit(); err != nil || h.CanUndo() || h.Undo() != ErrTransactionOpen {
+		t.Fatalf("inner commit closed the transaction: %v", err)
+	}
+	h.Do(appendText{"d"})
+	h.Commit()
+	if h.State() != "abcd" || h.UndoCount() != 2 {
+		t.Fatalf("state %q with %v entries", h.State(), h.UndoCount())
+	}
+	checkHistory(h, []func() error{h.Undo, h.Redo, h.Undo, h.Undo}, []string{"a", "abcd", "a", ""}, t)
+	if h.Commit() != ErrNoTransaction || h.Rollback() != ErrNoTransaction {
+		t.Fatalf("closed a transaction that is not open")
+	}
+
+	h.Redo()
+	h.Begin()
+	h.Begin()
+	h.Do(appendText{"x"})
+	h.Do(appendText{"y"})
+	if err := h.Rollback(); err != nil || h.State() != "a" || h.UndoCount() != 1 || h.RedoCount() != 0 {
+		t.Fatalf("rollback left %q: %v", h.State(), err)
+	}
+	// empty transactions are not recorded
+	h.Begin()
+	h.Commit()
+	if h.UndoCount() != 1 {
+		t.Fatalf("%v entries after an empty transaction", h.UndoCount())
+	}
+}
+
+func TestHistoryFailedUndo(t *testing.T) {
+	h := NewHistory("", 0)
+	h.Begin()
+	h.Do(appendText{"ab"})
+	h.Do(appendText{"cd"})
+	h.Commit()
+	// the first undo succeeds, the second fails as the text was changed underneath
+	h.Do(FuncCommand[string]{
+		DoFunc:   func(state string) (string, error) { return "x" + state[1:], nil },
+		UndoFunc: func(state string) (string, error) { return state, errors.New("cannot undo") },
+	})
+	if err := h.Undo(); err == nil || h.State() != "xbcd" {
+		t.Fatalf("failed undo left %q: %v", h.State(), err)
+	}
+	h = NewHistory("x", 0)
+	h.Begin()
+	h.Do(appendText{"ab"})
+	h.Do(appendText{"cd"})
+	h.Commit()
+	h.state = "xabyy"
+	if err := h.Undo(); err == nil || h.State() != "xabyy" || h.UndoCount() != 1 {
+		t.Fatalf("partly undone transaction left %q: %v", h.State(), err)
+	}
+
+	// reverting the failed entry fails too, so both errors are reported
+	errUndo, errDo := errors.New("cannot undo"), errors.New("cannot do")
+	failUndo, failDo := false, false
+	add := func(n int) FuncCommand[int] {
+		return FuncCommand[int]{
+			DoFunc: func(state int) (int, error) {
+				if failDo && n == 10 {
+					return state, errDo
+				}
+				return state + n, nil
+			},
+			UndoFunc: func(state int) (int, error) {
+				if failUndo && n == 1 {
+					return state, errUndo
+				}
+				return state - n, nil
+			},
+		}
+	}
+	counter := NewHistory(0, 0)
+	counter.Begin()
+	counter.Do(add(1))
+	counter.Do(add(10))
+	counter.Commit()
+	failUndo, failDo = true, true
+	if err := counter.Undo(); !errors.Is(err, errUndo) || !errors.Is(err, errDo) || counter.State() != 1 {
+		t.Fatalf("failed undo and redo left %v: %v", counter.State(), err)
+	}
+	failUndo, failDo = false, false
+	counter = NewHistory(0, 0)
+	counter.Begin()
+	counter.Do(add(1))
+	counter.Do(add(10))
+	counter.Commit()
+	counter.Undo()
+	failUndo, failDo = true, true
+	if err := counter.Redo(); !errors.Is(err, errUndo) || !errors.Is(err, errDo) || counter.State() != 1 {
+		t.Fatalf("failed redo and undo left %v: %v", counter.State(), err)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	h := NewHistory("", 3)
+	for _, text := range []string{"a", "b", "c", "d", "e"} {
+		h.Do(appendText{text})
+	}
+	for h.CanUndo() {
+		h.Undo()
+	}
+	if h.State() != "ab" || h.RedoCount() != 3 {
+		t.Fatalf("state %q after undoing everything", h.State())
+	}
+
+	// forgotten entries are dropped in batches, redone ones count too
+	h = NewHistory("", 4)
+	expected := ""
+	for i := 0; i < 100; i++ {
+		text := string(rune('a' + i%26))
+		h.Do(appendText{text})
+		expected += text
+		if i%7 == 0 {
+			h.Undo()
+			h.Redo()
+		}
+		if h.UndoCount() != min(i+1, 4) || h.undo.Count() > 8 {
+			t.Fatalf("%v undo entries, %v kept, after %v commands", h.UndoCount(), h.undo.Count(), i+1)
+		}
+	}
+	for h.CanUndo() {
+		h.Undo()
+	}
+	if h.State() != expected[:96] || h.Undo() != ErrNothingToUndo {
+		t.Fatalf("state %q after undoing everything", h.State())
+	}
+}
+
+func TestHistorySnapshot(t *testing.T) {
+	h := NewHistory("", 10)
+	h.Do(appendText{"a"})
+	h.Begin()
+	h.Do(appendText{"b"})
+	if _, err := h.Snapshot(); err != ErrTransactionOpen {
+		t.Fatalf("snapshot of an open transaction: %v", err)
+	}
+	h.Do(appendText{"c"})
+	h.Commit()
+	h.Do(appendText{"d"})
+	h.Undo()
+	snapshot, err := h.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// persist with the concrete command type
+	type stored struct {
+		State      string
+		Undo, Redo [][]appendText
+		Limit      int
+	}
+	toStored := func(entries [][]Command[string]) [][]appendText {
+		result := [][]appendText{}
+		for _, entry := range entries {
+			commands := []appendText{}
+			for _, command := range entry {
+				commands = append(commands, command.(appendText))
+			}
+			result = append(result, commands)
+		}
+		return result
+	}
+	fromStored := func(entries [][]appendText) [][]Command[string] {
+		result := [][]Command[string]{}
+		for _, entry := range entries {
+			commands := []Command[string]{}
+			for _, command := range entry {
+				commands = append(commands, command)
+			}
+			result = append(result, commands)
+		}
+		return result
+	}
+	data, err := json.Marshal(stored{snapshot.State, toStored(snapshot.Undo), toStored(snapshot.Redo), snapshot.Limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded stored
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	restored := NewHistory("ignored", 0)
+	restored.Do(appendText{"z"})
+	err = restored.Restore(HistorySnapshot[string]{loaded.State, fromStored(loaded.Undo), fromStored(loaded.Redo), loaded.Limit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkHistory(restored, []func() error{restored.Redo, restored.Undo, restored.Undo, restored.Undo}, []string{"abcd", "abc", "a", ""}, t)
+
+	// restoring keeps the newest entries within the limit
+	snapshot.Limit = 1
+	h.Restore(snapshot)
+	if h.UndoCount() != 1 || h.Undo() != nil || h.State() != "a" {
+		t.Fatalf("restored %v entries, state %q", h.UndoCount(), h.State())
+	}
+}
@@ -0,0 +1,125 @@
+package collections
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Keeps the producer and consumer positions on separate cache lines
+type cacheLinePad [64]byte
+
+// Bounded multi-producer multi-consumer queue without locks (Dmitry Vyukov's design).
+// Every cell carries a sequence number telling producers and consumers whose turn
+// it is, so a position is claimed with a single compare-and-swap. Positions are
+// 64 bit counters that never wrap in practice, which rules out ABA.
+// The zero value has no cells and cannot be used, see NewLockFreeQueue.
+type LockFreeQueue[T any] struct {
+	_       cacheLinePad
+	cells   []lockFreeCell[T]
+	mask    uint64
+	_       cacheLinePad
+	enqueue atomic.Uint64
+	_       cacheLinePad
+	dequeue atomic.Uint64
+	_       cacheLinePad
+}
+
+var _ Queuer[int] = (*LockFreeQueue[int])(nil)
+
+type lockFreeCell[T any] struct {
+	// Equal to the position for a producer's turn, position+1 for a consumer's
+	sequence atomic.Uint64
+	item     T
+}
+
+// Queue holding at most capacity items, rounded up to a power of two
+func NewLockFreeQueue[T any](capacity int) *LockFreeQueue[T] {
+	if capacity < 1 {
+		panic("LockFreeQueue capacity must be positive")
+	}
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+	q := &LockFreeQueue[T]{cells: make([]lockFreeCell[T], size), mask: uint64(size - 1)}
+	for i := range q.cells {
+		q.cells[i].sequence.Store(uint64(i))
+	}
+	return q
+}
+
+// Spins until there is space
+func (q *LockFreeQueue[T]) Push(item T) {
+	for q.TryPush(item) != nil {
+		runtime.Gosched()
+	}
+}
+
+// Returns ErrQueueEmpty rather than waiting for an item
+func (q *LockFreeQueue[T]) Pop() (T, error) {
+	return q.TryPop()
+}
+
+// ErrQueueFull if there is no space
+func (q *LockFreeQueue[T]) TryPush(item T) error {
+	position := q.enqueue.Load()
+	for {
+		cell := &q.cells[position&q.mask]
+		sequence := cell.sequence.Load()
+		switch difference := int64(sequence - position); {
+		case difference == 0:
+			if q.enqueue.CompareAndSwap(position, position+1) {
+				cell.item = item
+				cell.sequence.Store(position + 1)
+				return nil
+			}
+			position = q.enqueue.Load()
+		case difference < 0:
+			// the cell still holds the item pushed a lap ago
+			return ErrQueueFull
+		default:
+			// another producer claimed the position
+			position = q.enqueue.Load()
+		}
+	}
+}
+
+// ErrQueueEmpty if there are no items
+func (q *LockFreeQueue[T]) TryPop() (T, error) {
+	position := q.dequeue.Load()
+	for {
+		cell := &q.cells[position&q.mask]
+		sequence := cell.sequence.Load()
+		switch difference := int64(sequence - (position + 1)); {
+		case difference == 0:
+			if q.dequeue.CompareAndSwap(position, position+1) {
+				item := cell.item
+				var zero T
+				cell.item = zero
+				// hand the cell to the producer of the next lap
+				cell.sequence.Store(position + q.mask + 1)
+				return item, nil
+			}
+			position = q.dequeue.Load()
+		case difference < 0:
+			var zero T
+			return zero, ErrQueueEmpty
+		default:
+			position = q.dequeue.Load()
+		}
+	}
+}
+
+// Number of items, only a snapshot while other goroutines use the queue
+func (q *LockFreeQueue[T]) Count() int {
+	dequeue := q.dequeue.Load()
+	enqueue := q.enqueue.Load()
+	if enqueue <= dequeue {
+		return 0
+	}
+	return min(int(enqueue-dequeue), len(q.cells))
+}
+
+func (q *LockFreeQueue[T]) Cap() int {
+	return len(q.cells)
+}
@@ -0,0 +1,125 @@
+package collections
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestLockFreeQueue(t *testing.T) {
+	q := NewLockFreeQueue[int](5)
+	if q.Cap() != 8 {
+		t.Fatalf("capacity %v, expected 8", q.Cap())
+	}
+	// several laps around the cells
+	for lap := 0; lap < 3; lap++ {
+		for i := 0; i < 8; i++ {
+			if err := q.TryPush(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := q.TryPush(8); err != ErrQueueFull {
+			t.Fatalf("expected ErrQueueFull, got %v", err)
+		}
+		if q.Count() != 8 {
+			t.Fatalf("count %v, expected 8", q.Count())
+		}
+		for i := 0; i < 8; i++ {
+			if item, err := q.Pop(); item != i || err != nil {
+				t.Fatalf("popped %v, %v, expected %v", item, err, i)
+			}
+		}
+		if _, err := q.TryPop(); err != ErrQueueEmpty {
+			t.Fatalf("expected ErrQueueEmpty, got %v", err)
+		}
+	}
+}
+
+// Run with -race: every item arrives exactly once and in order per producer
+func TestLockFreeQueueConcurrent(t *testing.T) {
+	const producers, consumers, items = 4, 4, 5000
+	type message struct{ producer, sequence int }
+	q := NewLockFreeQueue[message](64)
+
+	var producing sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func(p int) {
+			defer producing.Done()
+			for i := 0; i < items; i++ {
+				q.Push(message{p, i})
+			}
+		}(p)
+	}
+
+	var mutex sync.Mutex
+	seen := map[message]bool{}
+	var consuming sync.WaitGroup
+	done := make(chan struct{})
+	for c := 0; c < consumers; c++ {
+		consuming.Add(1)
+		go func() {
+			defer consuming.Done()
+			last := make([]int, producers)
+			for p := range last {
+				last[p] = -1
+			}
+			for {
+				m, err := q.Pop()
+				if err != nil {
+					select {
+					case <-done:
+						if q.Count() == 0 {
+							return
+						}
+					default:
+					}
+					runtime.Gosched()
+					continue
+				}
+				mutex.Lock()
+				if seen[m] || m.sequence <= last[m.producer] {
+					t.Errorf("received %v twice or out of order", m)
+				}
+				seen[m] = true
+				mutex.Unlock()
+				last[m.producer] = m.sequence
+			}
+		}()
+	}
+	producing.Wait()
+	close(done)
+	consuming.Wait()
+	if len(seen) != producers*items {
+		t.Fatalf("received %v messages, expected %v", len(seen), producers*items)
+	}
+}
+
+type mutexQueue struct {
+	sync.Mutex
+	Queue[int]
+}
+
+func BenchmarkLockFreeQueue(b *testing.B) {
+	q := NewLockFreeQueue[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+			q.Pop()
+		}
+	})
+}
+
+func BenchmarkMutexQueue(b *testing.B) {
+	q := &mutexQueue{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Lock()
+			q.Push(1)
+			q.Unlock()
+			q.Lock()
+			q.Pop()
+			q.Unlock()
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package collections
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Unbounded stack without locks (Treiber stack): the top is replaced by a single
+// compare-and-swap. Every push allocates a fresh node and popped nodes are never
+// reused while referenced, as the garbage collector guarantees, so a stale top
+// can never compare equal to a new one (no ABA).
+// The zero value is an empty stack.
+type LockFreeStack[T any] struct {
+	top   atomic.Pointer[lockFreeNode[T]]
+	count atomic.Int64
+}
+
+type lockFreeNode[T any] struct {
+	item T
+	next *lockFreeNode[T]
+}
+
+func (s *LockFreeStack[T]) Push(item T) {
+	node := &lockFreeNode[T]{item: item}
+	for {
+		node.next = s.top.Load()
+		if s.top.CompareAndSwap(node.next, node) {
+			s.count.Add(1)
+			return
+		}
+	}
+}
+
+func (s *LockFreeStack[T]) Pop() (T, error) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			var v T
+			return v, errors.New("Stack is empty")
+		}
+		if s.top.CompareAndSwap(top, top.next) {
+			s.count.Add(-1)
+			return top.item, nil
+		}
+	}
+}
+
+func (s *LockFreeStack[T]) Peek() (T, error) {
+	top := s.top.Load()
+	if top == nil {
+		var v T
+		return v, errors.New("Stack is empty")
+	}
+	return top.item, nil
+}
+
+// Number of items, only a snapshot while other goroutines use the stack
+func (s *LockFreeStack[T]) Count() int {
+	return max(0, int(s.count.Load()))
+}
+
+func (s *LockFreeStack[T]) Empty() bool {
+	return s.top.Load() == nil
+}
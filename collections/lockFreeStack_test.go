@@ -0,0 +1,98 @@
+package collections
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLockFreeStack(t *testing.T) {
+	s := &LockFreeStack[string]{}
+	if _, err := s.Pop(); err == nil || !s.Empty() {
+		t.Fatalf("popped from an empty stack")
+	}
+	for _, item := range []string{"a", "b", "c"} {
+		s.Push(item)
+	}
+	if top, _ := s.Peek(); top != "c" || s.Count() != 3 {
+		t.Fatalf("peeked %v with %v items", top, s.Count())
+	}
+	for _, expected := range []string{"c", "b", "a"} {
+		if item, err := s.Pop(); item != expected || err != nil {
+			t.Fatalf("popped %v, %v, expected %v", item, err, expected)
+		}
+	}
+}
+
+// Run with -race: interleaved pushes and pops lose and duplicate nothing
+func TestLockFreeStackConcurrent(t *testing.T) {
+	const workers, items = 8, 5000
+	s := &LockFreeStack[int]{}
+	popped := make([][]int, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < items; i++ {
+				s.Push(w*items + i)
+				if i%2 == 1 {
+					for j := 0; j < 2; j++ {
+						if item, err := s.Pop(); err == nil {
+							popped[w] = append(popped[w], item)
+						}
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	seen := map[int]bool{}
+	for _, items := range popped {
+		for _, item := range items {
+			if seen[item] {
+				t.Fatalf("%v popped twice", item)
+			}
+			seen[item] = true
+		}
+	}
+	for !s.Empty() {
+		item, _ := s.Pop()
+		if seen[item] {
+			t.Fatalf("%v popped twice", item)
+		}
+		seen[item] = true
+	}
+	if len(seen) != workers*items || s.Count() != 0 {
+		t.Fatalf("%v items popped, expected %v, %v left", len(seen), workers*items, s.Count())
+	}
+}
+
+type mutexStack struct {
+	sync.Mutex
+	Stack[int]
+}
+
+func BenchmarkLockFreeStack(b *testing.B) {
+	s := &LockFreeStack[int]{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Push(1)
+			s.Pop()
+		}
+	})
+}
+
+func BenchmarkMutexStack(b *testing.B) {
+	s := &mutexStack{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Lock()
+			s.Push(1)
+			s.Unlock()
+			s.Lock()
+			s.Pop()
+			s.Unlock()
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package collections
+
+import (
+	"math/bits"
+)
+
+// Array based heap whose levels alternate between min and max levels, so that
+// both the smallest and the largest priority can be popped in O(log(n)).
+// Elements on a min level come before all their descendants, elements on a
+// max level after them.
+type MinMaxHeap[T, P any] struct {
+	handles []*PriorityHandle[T, P]
+	compare func(a, b P) int
+	owner   *heapOwner
+}
+
+func NewMinMaxHeap[T, P any](compare func(a, b P) int) *MinMaxHeap[T, P] {
+	return &MinMaxHeap[T, P]{compare: compare, owner: &heapOwner{}}
+}
+
+func (h *MinMaxHeap[T, P]) Push(value T, priority P) *PriorityHandle[T, P] {
+	handle := &PriorityHandle[T, P]{Value: value, priority: priority, index: len(h.handles), owner: h.owner}
+	h.handles = append(h.handles, handle)
+	h.bubbleUp(handle.index)
+	return handle
+}
+
+// Pops the value that comes first
+func (h *MinMaxHeap[T, P]) Pop() (T, P, error) {
+	return h.popAt(0)
+}
+
+// Pops the value that comes last
+func (h *MinMaxHeap[T, P]) PopMax() (T, P, error) {
+	return h.popAt(h.maxIndex())
+}
+
+func (h *MinMaxHeap[T, P]) Peek() (T, P, error) {
+	return h.peekAt(0)
+}
+
+func (h *MinMaxHeap[T, P]) PeekMax() (T, P, error) {
+	return h.peekAt(h.maxIndex())
+}
+
+// Reports whether the handle's value is still queued
+func (h *MinMaxHeap[T, P]) Contains(handle *PriorityHandle[T, P]) bool {
+	return handle.in(h.owner)
+}
+
+// Lowers the priority of a queued value, O(log(n)).
+// Fails if the new priority would come after the current one.
+func (h *MinMaxHeap[T, P]) DecreaseKey(handle *PriorityHandle[T, P], priority P) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	if h.compare(priority, handle.priority) > 0 {
+		return ErrPriorityIncreased
+	}
+	handle.priority = priority
+	h.fix(handle.index)
+	return nil
+}
+
+// Changes the priority of a queued value in either direction, O(log(n))
+func (h *MinMaxHeap[T, P]) Update(handle *PriorityHandle[T, P], priority P) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	handle.priority = priority
+	h.fix(handle.index)
+	return nil
+}
+
+func (h *MinMaxHeap[T, P]) Remove(handle *PriorityHandle[T, P]) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	index, last := handle.index, len(h.handles)-1
+	h.swap(index, last)
+	h.handles[last] = nil
+	h.handles = h.handles[:last]
+	handle.index = -1
+	if index < last {
+		h.fix(index)
+	}
+	return nil
+}
+
+func (h *MinMaxHeap[T, P]) Count() int {
+	return len(h.handles)
+}
+
+func (h *MinMaxHeap[T, P]) Empty() bool {
+	return len(h.handles) == 0
+}
+
+// Position of the last value: the larger child of the root, if any
+func (h *MinMaxHeap[T, P]) maxIndex() int {
+	switch len(h.handles) {
+	case 0, 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.less(1, 2) {
+		return 2
+	}
+	return 1
+}
+
+func (h *MinMaxHeap[T, P]) popAt(index int) (T, P, error) {
+	if len(h.handles) == 0 {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	handle := h.handles[index]
+	h.Remove(handle)
+	return handle.Value, handle.priority, nil
+}
+
+func (h *MinMaxHeap[T, P]) peekAt(index int) (T, P, error) {
+	if len(h.handles) == 0 {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	return h.handles[index].Value, h.handles[index].priority, nil
+}
+
+func (h *MinMaxHeap[T, P]) less(i, j int) bool {
+	return h.compare(h.handles[i].priority, h.handles[j].priority) < 0
+}
+
+// Reports whether i has to be above j on the min or max side
+func (h *MinMaxHeap[T, P]) before(i, j int, max bool) bool {
+	if max {
+		return h.less(j, i)
+	}
+	return h.less(i, j)
+}
+
+func (h *MinMaxHeap[T, P]) swap(i, j int) {
+	h.handles[i], h.handles[j] = h.handles[j], h.handles[i]
+	h.handles[i].index = i
+	h.handles[j].index = j
+}
+
+func isMaxLevel(i int) bool {
+	return bits.Len(uint(i+1))%2 == 0
+}
+
+// Restores the order after the priority at i changed: the element moves up if
+// it violates an ancestor, and whatever ends up at i moves down
+func (h *MinMaxHeap[T, P]) fix(i int) {
+	h.bubbleUp(i)
+	h.trickleDown(i)
+}
+
+func (h *MinMaxHeap[T, P]) bubbleUp(i int) {
+	if i == 0 {
+		return
+	}
+	max := isMaxLevel(i)
+	parent := (i - 1) / 2
+	if h.before(parent, i, max) {
+		// belongs to the other side
+		h.swap(i, parent)
+		i, max = parent, !max
+	}
+	for i > 2 {
+		grandparent := ((i-1)/2 - 1) / 2
+		if !h.before(i, grandparent, max) {
+			return
+		}
+		h.swap(i, grandparent)
+		i = grandparent
+	}
+}
+
+func (h *MinMaxHeap[T, P]) trickleDown(i int) {
+	max := isMaxLevel(i)
+	n := len(h.handles)
+	for {
+		// first of the children and grandchildren
+		m := -1
+		for _, j := range [...]int{2*i + 1, 2*i + 2, 4*i + 3, 4*i + 4, 4*i + 5, 4*i + 6} {
+			if j < n && (m == -1 || h.before(j, m, max)) {
+				m = j
+			}
+		}
+		if m == -1 || !h.before(m, i, max) {
+			return
+		}
+		h.swap(m, i)
+		if m <= 2*i+2 {
+			return
+		}
+		if parent := (m - 1) / 2; h.before(parent, m, max) {
+			h.swap(m, parent)
+		}
+		i = m
+	}
+}
@@ -0,0 +1,68 @@
+package collections
+
+import (
+	"cmp"
+	"errors"
+)
+
+// Stack that also tracks the minimum and maximum of its items in O(1):
+// every entry keeps the extremes of the items up to it.
+type MinMaxStack[T cmp.Ordered] struct {
+	entries []minMaxEntry[T]
+}
+
+type minMaxEntry[T cmp.Ordered] struct {
+	item, min, max T
+}
+
+func (s *MinMaxStack[T]) Push(item T) {
+	entry := minMaxEntry[T]{item, item, item}
+	if len(s.entries) > 0 {
+		top := s.entries[len(s.entries)-1]
+		entry.min, entry.max = min(top.min, item), max(top.max, item)
+	}
+	s.entries = append(s.entries, entry)
+}
+
+func (s *MinMaxStack[T]) Pop() (T, error) {
+	if len(s.entries) == 0 {
+		var v T
+		return v, errors.New("Stack is empty")
+	}
+	lastIndex := len(s.entries) - 1
+	popped := s.entries[lastIndex].item
+	s.entries = s.entries[:lastIndex]
+	return popped, nil
+}
+
+func (s *MinMaxStack[T]) Peek() (T, error) {
+	top, err := s.top()
+	return top.item, err
+}
+
+// Smallest item on the stack
+func (s *MinMaxStack[T]) Min() (T, error) {
+	top, err := s.top()
+	return top.min, err
+}
+
+// Largest item on the stack
+func (s *MinMaxStack[T]) Max() (T, error) {
+	top, err := s.top()
+	return top.max, err
+}
+
+func (s *MinMaxStack[T]) Count() int {
+	return len(s.entries)
+}
+
+func (s *MinMaxStack[T]) Empty() bool {
+	return len(s.entries) == 0
+}
+
+func (s *MinMaxStack[T]) top() (minMaxEntry[T], error) {
+	if len(s.entries) == 0 {
+		return minMaxEntry[T]{}, errors.New("Stack is empty")
+	}
+	return s.entries[len(s.entries)-1], nil
+}
@@ -0,0 +1,43 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	s := MinMaxStack[int]{}
+	for _, read := range []func() (int, error){s.Pop, s.Peek, s.Min, s.Max} {
+		if _, err := read(); err == nil {
+			t.Fatalf("read from an empty stack")
+		}
+	}
+	model := []int{}
+	for i := 0; i < 5000; i++ {
+		if random.Intn(3) > 0 || len(model) == 0 {
+			item := random.Intn(1000)
+			s.Push(item)
+			model = append(model, item)
+		} else {
+			item, err := s.Pop()
+			if err != nil || item != model[len(model)-1] {
+				t.Fatalf("popped %v, expected %v", item, model[len(model)-1])
+			}
+			model = model[:len(model)-1]
+		}
+		if s.Count() != len(model) || s.Empty() != (len(model) == 0) {
+			t.Fatalf("count %v, expected %v", s.Count(), len(model))
+		}
+		if len(model) == 0 {
+			continue
+		}
+		top, _ := s.Peek()
+		smallest, _ := s.Min()
+		largest, _ := s.Max()
+		if top != model[len(model)-1] || smallest != slices.Min(model) || largest != slices.Max(model) {
+			t.Fatalf("top %v, min %v, max %v of %v", top, smallest, largest, model)
+		}
+	}
+}
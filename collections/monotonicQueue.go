@@ -0,0 +1,86 @@
+package collections
+
+import (
+	"cmp"
+	"errors"
+)
+
+// FIFO queue that also tracks the minimum and maximum of its items, as needed
+// for the extremes of a sliding window. Besides the items it keeps the ones
+// that can still become the minimum in increasing order and the ones that can
+// still become the maximum in decreasing order, so every operation takes O(1)
+// amortized time.
+type MonotonicQueue[T cmp.Ordered] struct {
+	items Deque[T]
+	mins  Deque[T]
+	maxes Deque[T]
+}
+
+var _ Queuer[int] = &MonotonicQueue[int]{}
+
+func (q *MonotonicQueue[T]) Push(item T) {
+	q.items.PushBack(item)
+	for !q.mins.Empty() {
+		if last, _ := q.mins.PeekBack(); last <= item {
+			break
+		}
+		q.mins.PopBack()
+	}
+	q.mins.PushBack(item)
+	for !q.maxes.Empty() {
+		if last, _ := q.maxes.PeekBack(); last >= item {
+			break
+		}
+		q.maxes.PopBack()
+	}
+	q.maxes.PushBack(item)
+}
+
+func (q *MonotonicQueue[T]) Pop() (T, error) {
+	item, err := q.items.PopFront()
+	if err != nil {
+		return item, errors.New("Queue is empty")
+	}
+	// equal items stay in the candidates, so the front is the popped one if equal
+	if first, _ := q.mins.PeekFront(); first == item {
+		q.mins.PopFront()
+	}
+	if first, _ := q.maxes.PeekFront(); first == item {
+		q.maxes.PopFront()
+	}
+	return item, nil
+}
+
+func (q *MonotonicQueue[T]) Peek() (T, error) {
+	item, err := q.items.PeekFront()
+	if err != nil {
+		return item, errors.New("Queue is empty")
+	}
+	return item, nil
+}
+
+// Smallest item in the queue
+func (q *MonotonicQueue[T]) Min() (T, error) {
+	item, err := q.mins.PeekFront()
+	if err != nil {
+		return item, errors.New("Queue is empty")
+	}
+	return item, nil
+}
+
+// Largest item in the queue
+func (q *MonotonicQueue[T]) Max() (T, error) {
+	item, err := q.maxes.PeekFront()
+	if err != nil {
+		return item, errors.New("Queue is empty")
+	}
+	return item, nil
+}
+
+func (q *MonotonicQueue[T]) Count() int {
+	return q.items.Count()
+}
+
+func (q *MonotonicQueue[T]) Empty() bool {
+	return q.items.Empty()
+}
@@ -0,0 +1,59 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestMonotonicQueue(t *testing.T) {
+	random := rand.New(rand.NewSource(12))
+	q := MonotonicQueue[float64]{}
+	for _, read := range []func() (float64, error){q.Pop, q.Peek, q.Min, q.Max} {
+		if _, err := read(); err == nil {
+			t.Fatalf("read from an empty queue")
+		}
+	}
+	model := []float64{}
+	for i := 0; i < 5000; i++ {
+		if random.Intn(2) > 0 || len(model) == 0 {
+			// few distinct values, so that equal items are common
+			item := float64(random.Intn(20)) / 2
+			q.Push(item)
+			model = append(model, item)
+		} else {
+			item, err := q.Pop()
+			if err != nil || item != model[0] {
+				t.Fatalf("popped %v, expected %v", item, model[0])
+			}
+			model = model[1:]
+		}
+		if q.Count() != len(model) || q.Empty() != (len(model) == 0) {
+			t.Fatalf("count %v, expected %v", q.Count(), len(model))
+		}
+		if len(model) == 0 {
+			continue
+		}
+		front, _ := q.Peek()
+		smallest, _ := q.Min()
+		largest, _ := q.Max()
+		if front != model[0] || smallest != slices.Min(model) || largest != slices.Max(model) {
+			t.Fatalf("front %v, min %v, max %v of %v", front, smallest, largest, model)
+		}
+	}
+}
+
+func BenchmarkMonotonicQueueWindow(b *testing.B) {
+	random := rand.New(rand.NewSource(0))
+	q := MonotonicQueue[int]{}
+	for i := 0; i < 1000; i++ {
+		q.Push(random.Int())
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(random.Int())
+		q.Pop()
+		q.Max()
+	}
+}
@@ -0,0 +1,163 @@
+package collections
+
+// Heap ordered tree in which every pop pairs up the children of the root.
+// Simple and fast in practice, and heaps can be melded in O(1).
+type PairingHeap[T, P any] struct {
+	root    *PriorityHandle[T, P]
+	count   int
+	compare func(a, b P) int
+	owner   *heapOwner
+	// Reused by mergePairs
+	pairs []*PriorityHandle[T, P]
+}
+
+// Nodes keep their leftmost child in child and their next sibling in right.
+// left is the previous sibling, or the parent for a leftmost child.
+
+func NewPairingHeap[T, P any](compare func(a, b P) int) *PairingHeap[T, P] {
+	return &PairingHeap[T, P]{compare: compare, owner: &heapOwner{}}
+}
+
+func (h *PairingHeap[T, P]) Push(value T, priority P) *PriorityHandle[T, P] {
+	handle := &PriorityHandle[T, P]{Value: value, priority: priority, owner: h.owner}
+	h.root = h.link(h.root, handle)
+	h.count++
+	return handle
+}
+
+func (h *PairingHeap[T, P]) Pop() (T, P, error) {
+	if h.root == nil {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	handle := h.root
+	h.Remove(handle)
+	return handle.Value, handle.priority, nil
+}
+
+func (h *PairingHeap[T, P]) Peek() (T, P, error) {
+	if h.root == nil {
+		var value T
+		var priority P
+		return value, priority, ErrHeapEmpty
+	}
+	return h.root.Value, h.root.priority, nil
+}
+
+// Reports whether the handle's value is still queued
+func (h *PairingHeap[T, P]) Contains(handle *PriorityHandle[T, P]) bool {
+	return handle.in(h.owner)
+}
+
+// Lowers the priority of a queued value by cutting its subtree off and
+// linking it with the root, o(log(n)) amortized.
+// Fails if the new priority would come after the current one.
+func (h *PairingHeap[T, P]) DecreaseKey(handle *PriorityHandle[T, P], priority P) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	if h.compare(priority, handle.priority) > 0 {
+		return ErrPriorityIncreased
+	}
+	handle.priority = priority
+	if handle != h.root {
+		h.detach(handle)
+		h.root = h.link(h.root, handle)
+	}
+	return nil
+}
+
+// Removes a queued value, O(log(n)) amortized
+func (h *PairingHeap[T, P]) Remove(handle *PriorityHandle[T, P]) error {
+	if !h.Contains(handle) {
+		return ErrUnknownHandle
+	}
+	children := h.mergePairs(handle.child)
+	if handle == h.root {
+		h.root = children
+	} else {
+		h.detach(handle)
+		h.root = h.link(h.root, children)
+	}
+	handle.child = nil
+	handle.index = -1
+	h.count--
+	return nil
+}
+
+// Moves all values of other into the heap in O(1), leaving other empty.
+// Handles returned by other stay valid for this heap.
+// Both heaps must use the same ordering.
+func (h *PairingHeap[T, P]) Meld(other *PairingHeap[T, P]) {
+	if other == h {
+		return
+	}
+	other.owner.into = h.owner
+	h.root = h.link(h.root, other.root)
+	h.count += other.count
+	other.root, other.count, other.owner = nil, 0, &heapOwner{}
+}
+
+func (h *PairingHeap[T, P]) Count() int {
+	return h.count
+}
+
+func (h *PairingHeap[T, P]) Empty() bool {
+	return h.count == 0
+}
+
+// Makes the tree whose root comes later the leftmost child of the other and
+// returns the root of the result. Both must be detached roots or nil.
+func (h *PairingHeap[T, P]) link(a, b *PriorityHandle[T, P]) *PriorityHandle[T, P] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.compare(b.priority, a.priority) < 0 {
+		a, b = b, a
+	}
+	b.left, b.right = a, a.child
+	if a.child != nil {
+		a.child.left = b
+	}
+	a.child = b
+	return a
+}
+
+// Takes the subtree rooted at a non-root node out of its sibling list
+func (h *PairingHeap[T, P]) detach(node *PriorityHandle[T, P]) {
+	if node.left.child == node {
+		node.left.child = node.right
+	} else {
+		node.left.right = node.right
+	}
+	if node.right != nil {
+		node.right.left = node.left
+	}
+	node.left, node.right = nil, nil
+}
+
+// Links the siblings pairwise from the left, then the pairs from the right
+// into a single tree
+func (h *PairingHeap[T, P]) mergePairs(first *PriorityHandle[T, P]) *PriorityHandle[T, P] {
+	h.pairs = h.pairs[:0]
+	for first != nil {
+		a, b := first, first.right
+		first = nil
+		if b != nil {
+			first = b.right
+			b.left, b.right = nil, nil
+		}
+		a.left, a.right = nil, nil
+		h.pairs = append(h.pairs, h.link(a, b))
+	}
+	var root *PriorityHandle[T, P]
+	for i := len(h.pairs) - 1; i >= 0; i-- {
+		root = h.link(h.pairs[i], root)
+		h.pairs[i] = nil
+	}
+	return root
+}
@@ -0,0 +1,603 @@
+package collections
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A PersistentQueue lives in a directory of its own:
+//
+//   - Segment files named after the sequence number of their first record,
+//     "00000000000000000000.seg". Items are appended to the newest segment until
+//     it reaches the segment size, then a new one is started.
+//   - An "acks" file whose first record holds the consumer offset, below which
+//     every item is acknowledged, followed by the sequence numbers of the items
+//     acknowledged out of order.
+//
+// Every record is its payload length and CRC-32C checksum, 4 bytes each in
+// little endian, followed by the payload. A torn record at the end of a file,
+// left by a crash during a write, is cut off when the queue is opened.
+
+var (
+	ErrCorruptRecord = errors.New("Record is corrupt")
+	ErrUnknownRecord = errors.New("Record is not awaiting acknowledgement")
+)
+
+// Converts items to and from the bytes stored in a PersistentQueue
+type Encoder[T any] interface {
+	Encode(item T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// Stores items as JSON
+type JSONEncoder[T any] struct{}
+
+func (JSONEncoder[T]) Encode(item T) ([]byte, error) {
+	return json.Marshal(item)
+}
+
+func (JSONEncoder[T]) Decode(data []byte) (T, error) {
+	var item T
+	err := json.Unmarshal(data, &item)
+	return item, err
+}
+
+type PersistentQueueOptions[T any] struct {
+	// JSONEncoder if nil
+	Encoder Encoder[T]
+	// Size in bytes after which a new segment file is started, 4 MiB if zero
+	SegmentSize int64
+	// Flush every append and acknowledgement to the disk before returning, so
+	// that they survive a power loss rather than just a crash of the process
+	Sync bool
+}
+
+const (
+	defaultSegmentSize = 4 << 20
+	recordHeaderSize   = 8
+	// Records longer than this are taken for corruption
+	maxRecordSize = 1 << 30
+	// Out of order acknowledgements the acks file may hold beyond the live ones
+	// before it is rewritten
+	maxStaleAcks = 1024
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// FIFO queue stored in append-only segment files, safe for concurrent use.
+// Receive hands out items together with their sequence number, and an item
+// stays in the queue until it is acknowledged with Ack. Items received but not
+// acknowledged before the queue was closed or the process crashed are
+// delivered again when the queue is reopened. Segments are deleted once all
+// their items are acknowledged.
+type PersistentQueue[T any] struct {
+	mutex       sync.Mutex
+	dir         string
+	encoder     Encoder[T]
+	segmentSize int64
+	sync        bool
+	closed      bool
+
+	// Sequence numbers of the first records of the segment files, oldest first.
+	// The last segment is the one appended to.
+	segments  []uint64
+	writer    *os.File
+	writeSize int64
+	// Segment index and file position of the next record to deliver
+	reader       *os.File
+	readSegment  int
+	readPosition int64
+
+	// Sequence numbers of the next record to deliver and to append
+	next, end uint64
+	// Every item below offset is acknowledged
+	offset uint64
+	// Acknowledged items at or above offset
+	acked map[uint64]bool
+	// Delivered items awaiting acknowledgement
+	inFlight map[uint64]bool
+	// Items left to deliver
+	count int
+
+	acks       *os.File
+	ackRecords int
+}
+
+var _ Queuer[int] = &PersistentQueue[int]{}
+
+// Opens the queue stored in dir, creating the directory if needed.
+// Unacknowledged items of a previous run are delivered again first.
+func OpenPersistentQueue[T any](dir string, options PersistentQueueOptions[T]) (*PersistentQueue[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	q := &PersistentQueue[T]{
+		dir:         dir,
+		encoder:     options.Encoder,
+		segmentSize: options.SegmentSize,
+		sync:        options.Sync,
+		acked:       map[uint64]bool{},
+		inFlight:    map[uint64]bool{},
+	}
+	if q.encoder == nil {
+		q.encoder = JSONEncoder[T]{}
+	}
+	if q.segmentSize <= 0 {
+		q.segmentSize = defaultSegmentSize
+	}
+	if err := q.open(); err != nil {
+		q.closeFiles()
+		return nil, err
+	}
+	return q, nil
+}
+
+// Appends the item, panics if the write fails or the queue is closed
+func (q *PersistentQueue[T]) Push(item T) {
+	if err := q.Append(item); err != nil {
+		panic(err)
+	}
+}
+
+// Receives the next item and acknowledges it at once, so it is not delivered
+// again after a crash. An item that fails to decode is dropped with the error.
+// ErrQueueEmpty if there is nothing to deliver.
+func (q *PersistentQueue[T]) Pop() (T, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	item, sequence, delivered, err := q.receive()
+	if delivered {
+		err = cmp.Or(err, q.ack(sequence))
+	}
+	return item, err
+}
+
+// Appends the item to the newest segment
+func (q *PersistentQueue[T]) Append(item T) error {
+	data, err := q.encoder.Encode(item)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxRecordSize {
+		return fmt.Errorf("Item of %v bytes is too large", len(data))
+	}
+	record := appendRecord(nil, data)
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.writeSize > 0 && q.writeSize+int64(len(record)) > q.segmentSize {
+		if err := q.roll(); err != nil {
+			return err
+		}
+	}
+	if _, err := q.writer.Write(record); err != nil {
+		// drop what made it to the file so that the next append starts cleanly
+		q.writer.Truncate(q.writeSize)
+		q.writer.Seek(q.writeSize, io.SeekStart)
+		return err
+	}
+	if q.sync {
+		if err := q.writer.Sync(); err != nil {
+			return err
+		}
+	}
+	q.writeSize += int64(len(record))
+	q.end++
+	q.count++
+	return nil
+}
+
+// Delivers the next item and its sequence number, to be passed to Ack once the
+// item is processed. ErrQueueEmpty if there is nothing to deliver. If the item
+// cannot be decoded the error is returned along with a valid sequence number,
+// so that the item can still be acknowledged.
+func (q *PersistentQueue[T]) Receive() (T, uint64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	item, sequence, _, err := q.receive()
+	return item, sequence, err
+}
+
+// Marks a received item as processed, so that it is never delivered again.
+// Deletes the segments whose items are all acknowledged.
+func (q *PersistentQueue[T]) Ack(sequence uint64) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.ack(sequence)
+}
+
+// Number of items left to deliver
+func (q *PersistentQueue[T]) Count() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.count
+}
+
+// Number of delivered items awaiting acknowledgement
+func (q *PersistentQueue[T]) InFlight() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.inFlight)
+}
+
+// Closes the files. Unacknowledged items are delivered again once reopened.
+// Closing twice has no effect.
+func (q *PersistentQueue[T]) Close() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	return q.closeFiles()
+}
+
+// Reports whether an item was delivered, in which case it awaits acknowledgement
+// even if it failed to decode. Must hold the mutex.
+func (q *PersistentQueue[T]) receive() (T, uint64, bool, error) {
+	var item T
+	if q.closed {
+		return item, 0, false, ErrQueueClosed
+	}
+	for q.next < q.end {
+		payload, err := readRecord(q.reader, q.readPosition)
+		if err == io.EOF && q.readSegment < len(q.segments)-1 {
+			if err := q.openReader(q.readSegment + 1); err != nil {
+				return item, 0, false, err
+			}
+			continue
+		}
+		if err != nil {
+			return item, 0, false, fmt.Errorf("Segment %v at %v: %w", q.segments[q.readSegment], q.readPosition, err)
+		}
+		sequence := q.next
+		q.next++
+		q.readPosition += recordHeaderSize + int64(len(payload))
+		if sequence < q.offset || q.acked[sequence] {
+			// acknowledged before the queue was reopened
+			continue
+		}
+		q.count--
+		q.inFlight[sequence] = true
+		item, err = q.encoder.Decode(payload)
+		return item, sequence, true, err
+	}
+	return item, 0, false, ErrQueueEmpty
+}
+
+// Must hold the mutex
+func (q *PersistentQueue[T]) ack(sequence uint64) error {
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if !q.inFlight[sequence] {
+		return ErrUnknownRecord
+	}
+	if err := q.writeAck(sequence); err != nil {
+		return err
+	}
+	delete(q.inFlight, sequence)
+	q.acked[sequence] = true
+	if sequence != q.offset {
+		return nil
+	}
+	for q.acked[q.offset] {
+		delete(q.acked, q.offset)
+		q.offset++
+	}
+	return q.compact()
+}
+
+func (q *PersistentQueue[T]) closeFiles() error {
+	var result error
+	for _, file := range []*os.File{q.writer, q.reader, q.acks} {
+		if file == nil {
+			continue
+		}
+		if q.sync && file != q.reader {
+			result = cmp.Or(result, file.Sync())
+		}
+		result = cmp.Or(result, file.Close())
+	}
+	return result
+}
+
+// Restores the state from the acks file and the segments
+func (q *PersistentQueue[T]) open() error {
+	if err := q.readAcks(); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name, found := strings.CutSuffix(entry.Name(), ".seg")
+		if !found {
+			continue
+		}
+		first, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Unexpected segment file %q", entry.Name())
+		}
+		q.segments = append(q.segments, first)
+	}
+	slices.Sort(q.segments)
+
+	if len(q.segments) == 0 {
+		q.segments = []uint64{q.offset}
+	}
+	// items of deleted segments were all acknowledged
+	q.offset = max(q.offset, q.segments[0])
+	last := q.segments[len(q.segments)-1]
+	if q.writer, err = os.OpenFile(q.segmentPath(last), os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return err
+	}
+	records, size, err := scanRecords(q.writer)
+	if err != nil {
+		return err
+	}
+	if err := q.writer.Truncate(size); err != nil {
+		return err
+	}
+	if _, err := q.writer.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+	q.writeSize = size
+	q.end = last + uint64(records)
+	q.offset = min(q.offset, q.end)
+	for sequence := range q.acked {
+		if sequence < q.offset || sequence >= q.end {
+			delete(q.acked, sequence)
+		}
+	}
+	for q.acked[q.offset] {
+		delete(q.acked, q.offset)
+		q.offset++
+	}
+	q.count = int(q.end-q.offset) - len(q.acked)
+
+	// start reading at the offset
+	readSegment := 0
+	for readSegment < len(q.segments)-1 && q.segments[readSegment+1] <= q.offset {
+		readSegment++
+	}
+	if err := q.openReader(readSegment); err != nil {
+		return err
+	}
+	for q.next < q.offset {
+		payload, err := readRecord(q.reader, q.readPosition)
+		if err != nil {
+			return fmt.Errorf("Segment %v at %v: %w", q.segments[q.readSegment], q.readPosition, err)
+		}
+		q.readPosition += recordHeaderSize + int64(len(payload))
+		q.next++
+	}
+	return q.rewriteAcks()
+}
+
+// Switches the reader to the start of the given segment
+func (q *PersistentQueue[T]) openReader(index int) error {
+	if q.reader != nil {
+		q.reader.Close()
+		q.reader = nil
+	}
+	reader, err := os.Open(q.segmentPath(q.segments[index]))
+	if err != nil {
+		return err
+	}
+	q.reader, q.readSegment, q.readPosition = reader, index, 0
+	q.next = q.segments[index]
+	return nil
+}
+
+// Starts a new segment for the following appends
+func (q *PersistentQueue[T]) roll() error {
+	if q.sync {
+		if err := q.writer.Sync(); err != nil {
+			return err
+		}
+	}
+	writer, err := os.OpenFile(q.segmentPath(q.end), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	// the appends to the segment only survive a power loss along with its entry
+	if q.sync {
+		if err := q.syncDir(); err != nil {
+			writer.Close()
+			return err
+		}
+	}
+	previous := q.writer
+	q.writer, q.writeSize = writer, 0
+	q.segments = append(q.segments, q.end)
+	return previous.Close()
+}
+
+// Flushes the creations and renamings of files in the directory to the disk
+func (q *PersistentQueue[T]) syncDir() error {
+	dir, err := os.Open(q.dir)
+	if err != nil {
+		return err
+	}
+	return cmp.Or(dir.Sync(), dir.Close())
+}
+
+// Deletes the segments before the one holding the oldest record that is
+// unacknowledged or not yet read
+func (q *PersistentQueue[T]) compact() error {
+	keep := min(q.offset, q.next)
+	drop := 0
+	for drop < len(q.segments)-1 && q.segments[drop+1] <= keep && drop < q.readSegment {
+		drop++
+	}
+	if drop == 0 && q.ackRecords <= len(q.acked)+maxStaleAcks {
+		return nil
+	}
+	// the offset must be on the disk before the segments go
+	if err := q.rewriteAcks(); err != nil {
+		return err
+	}
+	for _, first := range q.segments[:drop] {
+		if err := os.Remove(q.segmentPath(first)); err != nil {
+			return err
+		}
+	}
+	q.segments = q.segments[drop:]
+	q.readSegment -= drop
+	return nil
+}
+
+func (q *PersistentQueue[T]) segmentPath(first uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d.seg", first))
+}
+
+func (q *PersistentQueue[T]) acksPath() string {
+	return filepath.Join(q.dir, "acks")
+}
+
+// Loads the offset and the out of order acknowledgements, ignoring a torn or
+// corrupt tail: the acknowledgements lost with it only cause redeliveries
+func (q *PersistentQueue[T]) readAcks() error {
+	file, err := os.Open(q.acksPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	position := int64(0)
+	for i := 0; ; i++ {
+		payload, err := readRecord(file, position)
+		if err != nil || len(payload) != 8 {
+			return nil
+		}
+		position += recordHeaderSize + 8
+		sequence := binary.LittleEndian.Uint64(payload)
+		if i == 0 {
+			q.offset = sequence
+		} else if sequence >= q.offset {
+			q.acked[sequence] = true
+		}
+	}
+}
+
+// Replaces the acks file by one holding just the offset and the live
+// acknowledgements
+func (q *PersistentQueue[T]) rewriteAcks() error {
+	sequences := []uint64{q.offset}
+	for sequence := range q.acked {
+		sequences = append(sequences, sequence)
+	}
+	slices.Sort(sequences[1:])
+	data := []byte{}
+	for _, sequence := range sequences {
+		data = appendRecord(data, binary.LittleEndian.AppendUint64(nil, sequence))
+	}
+
+	temporary := q.acksPath() + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(temporary, q.acksPath()); err != nil {
+		file.Close()
+		return err
+	}
+	if q.acks != nil {
+		q.acks.Close()
+	}
+	q.acks, q.ackRecords = file, len(sequences)
+	if q.sync {
+		return q.syncDir()
+	}
+	return nil
+}
+
+func (q *PersistentQueue[T]) writeAck(sequence uint64) error {
+	if _, err := q.acks.Write(appendRecord(nil, binary.LittleEndian.AppendUint64(nil, sequence))); err != nil {
+		return err
+	}
+	q.ackRecords++
+	if q.sync {
+		return q.acks.Sync()
+	}
+	return nil
+}
+
+func appendRecord(buffer, payload []byte) []byte {
+	buffer = binary.LittleEndian.AppendUint32(buffer, uint32(len(payload)))
+	buffer = binary.LittleEndian.AppendUint32(buffer, crc32.Checksum(payload, castagnoli))
+	return append(buffer, payload...)
+}
+
+// Payload of the record at the given position. io.EOF if the file ends there,
+// ErrCorruptRecord if the record is torn or fails its checksum.
+func readRecord(r io.ReaderAt, position int64) ([]byte, error) {
+	header := make([]byte, recordHeaderSize)
+	if n, err := r.ReadAt(header, position); n < recordHeaderSize {
+		if n == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+		return nil, ErrCorruptRecord
+	}
+	length := binary.LittleEndian.Uint32(header)
+	if length > maxRecordSize {
+		return nil, ErrCorruptRecord
+	}
+	payload := make([]byte, length)
+	if n, err := r.ReadAt(payload, position+recordHeaderSize); n < len(payload) {
+		if err != io.EOF {
+			return nil, err
+		}
+		return nil, ErrCorruptRecord
+	}
+	if crc32.Checksum(payload, castagnoli) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, ErrCorruptRecord
+	}
+	return payload, nil
+}
+
+// Counts the intact records from the start of the file, and returns the size
+// they take up
+func scanRecords(r io.ReaderAt) (int, int64, error) {
+	records, position := 0, int64(0)
+	for {
+		payload, err := readRecord(r, position)
+		if err == io.EOF || err == ErrCorruptRecord {
+			return records, position, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		records++
+		position += recordHeaderSize + int64(len(payload))
+	}
+}
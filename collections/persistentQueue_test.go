@@ -0,0 +1,321 @@
+package collections
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func openTestQueue(dir string, t *testing.T) *PersistentQueue[int] {
+	q, err := OpenPersistentQueue(dir, PersistentQueueOptions[int]{SegmentSize: 256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func segmentFiles(dir string, t *testing.T) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// Receives everything left, without acknowledging
+func receiveAll(q *PersistentQueue[int], t *testing.T) ([]int, []uint64) {
+	items, sequences := []int{}, []uint64{}
+	for {
+		item, sequence, err := q.Receive()
+		if err == ErrQueueEmpty {
+			return items, sequences
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		items, sequences = append(items, item), append(sequences, sequence)
+	}
+}
+
+func TestPersistentQueue(t *testing.T) {
+	dir := t.TempDir()
+	q := openTestQueue(dir, t)
+	if _, err := q.Pop(); err != ErrQueueEmpty {
+		t.Fatalf("pop from an empty queue: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		q.Push(i)
+	}
+	if q.Count() != 200 || len(segmentFiles(dir, t)) < 5 {
+		t.Fatalf("%v items in %v segments", q.Count(), len(segmentFiles(dir, t)))
+	}
+	for i := 0; i < 150; i++ {
+		if item, err := q.Pop(); err != nil || item != i {
+			t.Fatalf("popped %v, expected %v: %v", item, i, err)
+		}
+	}
+	if q.Count() != 50 || q.InFlight() != 0 {
+		t.Fatalf("count %v, in flight %v", q.Count(), q.InFlight())
+	}
+	// consumed segments are gone
+	if files := len(segmentFiles(dir, t)); files > 5 {
+		t.Fatalf("%v segments left for 50 items", files)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	if _, err := q.Pop(); err != ErrQueueClosed {
+		t.Fatalf("pop from a closed queue: %v", err)
+	}
+	if err := q.Append(1); err != ErrQueueClosed {
+		t.Fatalf("append to a closed queue: %v", err)
+	}
+
+	q = openTestQueue(dir, t)
+	defer q.Close()
+	q.Push(200)
+	items, _ := receiveAll(q, t)
+	expected := []int{}
+	for i := 150; i <= 200; i++ {
+		expected = append(expected, i)
+	}
+	if !slices.Equal(items, expected) {
+		t.Fatalf("reopened queue delivered %v", items)
+	}
+}
+
+func TestPersistentQueueRedelivery(t *testing.T) {
+	dir := t.TempDir()
+	q := openTestQueue(dir, t)
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+	}
+	// acknowledge every third of the first 60 items, out of order
+	received := map[int]uint64{}
+	for i := 0; i < 60; i++ {
+		item, sequence, err := q.Receive()
+		if err != nil || item != i {
+			t.Fatalf("received %v, expected %v: %v", item, i, err)
+		}
+		received[item] = sequence
+	}
+	expected := []int{}
+	for i := 59; i >= 0; i-- {
+		if i%3 == 0 {
+			if err := q.Ack(received[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if i >= 60 || i%3 != 0 {
+			expected = append(expected, i)
+		}
+	}
+	if err := q.Ack(received[0]); err != ErrUnknownRecord {
+		t.Fatalf("acknowledged twice: %v", err)
+	}
+	if err := q.Ack(1000); err != ErrUnknownRecord {
+		t.Fatalf("acknowledged an unknown record: %v", err)
+	}
+	if q.Count() != 40 || q.InFlight() != 40 {
+		t.Fatalf("count %v, in flight %v", q.Count(), q.InFlight())
+	}
+
+	// crash: reopen without closing
+	crashed := q
+	defer crashed.Close()
+	q = openTestQueue(dir, t)
+	if q.Count() != len(expected) {
+		t.Fatalf("count %v after reopening, expected %v", q.Count(), len(expected))
+	}
+	items, sequences := receiveAll(q, t)
+	if !slices.Equal(items, expected) {
+		t.Fatalf("redelivered %v, expected %v", items, expected)
+	}
+	for _, sequence := range sequences[:10] {
+		q.Ack(sequence)
+	}
+	q.Close()
+
+	q = openTestQueue(dir, t)
+	items, sequences = receiveAll(q, t)
+	if !slices.Equal(items, expected[10:]) {
+		t.Fatalf("redelivered %v, expected %v", items, expected[10:])
+	}
+	for _, sequence := range sequences {
+		if err := q.Ack(sequence); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files := segmentFiles(dir, t); len(files) != 1 {
+		t.Fatalf("segments %v left after acknowledging everything", files)
+	}
+	q.Close()
+
+	q = openTestQueue(dir, t)
+	defer q.Close()
+	if items, _ := receiveAll(q, t); len(items) != 0 {
+		t.Fatalf("acknowledged items %v delivered again", items)
+	}
+}
+
+func TestPersistentQueueTornWrite(t *testing.T) {
+	for _, damage := range []string{"torn header", "torn payload", "bad checksum"} {
+		dir := t.TempDir()
+		q := openTestQueue(dir, t)
+		for i := 0; i < 5; i++ {
+			q.Push(i)
+		}
+		q.Close()
+
+		files := segmentFiles(dir, t)
+		last := files[len(files)-1]
+		data, _ := os.ReadFile(last)
+		switch damage {
+		case "torn header":
+			data = append(data, 3, 0, 0)
+		case "torn payload":
+			data = appendRecord(data, []byte("123456"))
+			data = data[:len(data)-2]
+		case "bad checksum":
+			data = appendRecord(data, []byte("5"))
+			data[len(data)-1] = '6'
+		}
+		os.WriteFile(last, data, 0o644)
+
+		q = openTestQueue(dir, t)
+		q.Push(5)
+		items, _ := receiveAll(q, t)
+		if !slices.Equal(items, []int{0, 1, 2, 3, 4, 5}) {
+			t.Fatalf("%v: delivered %v", damage, items)
+		}
+		q.Close()
+	}
+}
+
+// Stores strings as they are, refusing empty ones on decoding
+type rawStrings struct{}
+
+func (rawStrings) Encode(item string) ([]byte, error) {
+	return []byte(item), nil
+}
+
+func (rawStrings) Decode(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("empty item")
+	}
+	return string(data), nil
+}
+
+func TestPersistentQueueEncoder(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenPersistentQueue(dir, PersistentQueueOptions[string]{Encoder: rawStrings{}, SegmentSize: 16, Sync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	for _, item := range []string{"a", "", "b"} {
+		if err := q.Append(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files := segmentFiles(dir, t); len(files) != 3 {
+		t.Fatalf("segments %v, expected one per item", files)
+	}
+	data, _ := os.ReadFile(segmentFiles(dir, t)[0])
+	if string(data[recordHeaderSize:recordHeaderSize+1]) != "a" {
+		t.Fatalf("item not stored by the encoder: %q", data)
+	}
+	if item, _ := q.Pop(); item != "a" {
+		t.Fatalf("popped %q", item)
+	}
+	_, sequence, err := q.Receive()
+	if err == nil {
+		t.Fatalf("decoded an empty item")
+	}
+	if err := q.Ack(sequence); err != nil {
+		t.Fatalf("acknowledging an undecodable item: %v", err)
+	}
+	if item, _ := q.Pop(); item != "b" {
+		t.Fatalf("popped %q", item)
+	}
+}
+
+func TestPersistentQueueConcurrent(t *testing.T) {
+	q := openTestQueue(t.TempDir(), t)
+	defer q.Close()
+	const producers, consumers, items = 4, 4, 500
+	var wait sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for i := 0; i < items; i++ {
+				q.Push(p*items + i)
+			}
+		}()
+	}
+	received := make([][]int, consumers)
+	var done sync.WaitGroup
+	stop := make(chan struct{})
+	for c := 0; c < consumers; c++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for {
+				item, sequence, err := q.Receive()
+				if err == ErrQueueEmpty {
+					select {
+					case <-stop:
+						if q.Count() == 0 {
+							return
+						}
+					default:
+					}
+					runtime.Gosched()
+					continue
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				received[c] = append(received[c], item)
+				q.Ack(sequence)
+			}
+		}()
+	}
+	wait.Wait()
+	close(stop)
+	done.Wait()
+
+	all := slices.Concat(received...)
+	slices.Sort(all)
+	if len(all) != producers*items {
+		t.Fatalf("received %v items", len(all))
+	}
+	for i, item := range all {
+		if item != i {
+			t.Fatalf("item %v received as %v", i, item)
+		}
+	}
+}
+
+func BenchmarkPersistentQueue(b *testing.B) {
+	q, err := OpenPersistentQueue(b.TempDir(), PersistentQueueOptions[int]{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer q.Close()
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+}
@@ -0,0 +1,74 @@
+package collections
+
+import (
+	"errors"
+)
+
+// Min-heap of values with separate priorities, ordered by a comparator as for
+// slices.SortFunc. Push returns a handle through which the priority of a queued
+// value can be lowered. The implementations trade the costs of Push, Pop and
+// DecreaseKey differently, see the ReadMe. Their zero values have no ordering
+// and cannot be used, the constructors set it.
+type PriorityHeap[T, P any] interface {
+	Push(value T, priority P) *PriorityHandle[T, P]
+	Pop() (T, P, error)
+	Peek() (T, P, error)
+	DecreaseKey(handle *PriorityHandle[T, P], priority P) error
+	Contains(handle *PriorityHandle[T, P]) bool
+	Count() int
+	Empty() bool
+}
+
+var (
+	_ PriorityHeap[int, int] = (*IndexedPriorityQueue[int, int])(nil)
+	_ PriorityHeap[int, int] = (*DaryHeap[int, int])(nil)
+	_ PriorityHeap[int, int] = (*PairingHeap[int, int])(nil)
+	_ PriorityHeap[int, int] = (*FibonacciHeap[int, int])(nil)
+	_ PriorityHeap[int, int] = (*MinMaxHeap[int, int])(nil)
+)
+
+var (
+	ErrHeapEmpty         = errors.New("Heap is empty")
+	ErrUnknownHandle     = errors.New("Handle is not in the heap")
+	ErrPriorityIncreased = errors.New("Priority is greater than the current one")
+)
+
+// A value queued in one of the heaps
+type PriorityHandle[T, P any] struct {
+	Value    T
+	priority P
+	// Position in array based heaps, 0 while queued in node based heaps,
+	// -1 once the value left the heap
+	index int
+	owner *heapOwner
+	// Links of node based heaps
+	parent, child, left, right *PriorityHandle[T, P]
+	degree                     int
+	marked                     bool
+}
+
+func (h *PriorityHandle[T, P]) Priority() P {
+	return h.priority
+}
+
+// Reports whether the handle is queued in the heap with the given owner
+func (h *PriorityHandle[T, P]) in(owner *heapOwner) bool {
+	return h != nil && h.index >= 0 && h.owner.find() == owner
+}
+
+// Identity of a heap that handles refer to. Melding a heap into another points
+// its owner at the other's, so that its handles move over in O(1).
+type heapOwner struct {
+	into *heapOwner
+}
+
+// The owner of the heap that absorbed this one, halving the path on the way
+func (o *heapOwner) find() *heapOwner {
+	for o.into != nil {
+		if o.into.into != nil {
+			o.into = o.into.into
+		}
+		o = o.into
+	}
+	return o
+}
@@ -0,0 +1,286 @@
+package collections
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+type removableHeap[T, P any] interface {
+	PriorityHeap[T, P]
+	Remove(handle *PriorityHandle[T, P]) error
+}
+
+var heapKinds = []struct {
+	name    string
+	newHeap func() removableHeap[int, int]
+}{
+	{"Binary", func() removableHeap[int, int] { return NewIndexedPriorityQueue[int](cmp.Compare[int]) }},
+	{"4-ary", func() removableHeap[int, int] { return NewDaryHeap[int](4, cmp.Compare[int]) }},
+	{"Pairing", func() removableHeap[int, int] { return NewPairingHeap[int](cmp.Compare[int]) }},
+	{"Fibonacci", func() removableHeap[int, int] { return NewFibonacciHeap[int](cmp.Compare[int]) }},
+	{"MinMax", func() removableHeap[int, int] { return NewMinMaxHeap[int](cmp.Compare[int]) }},
+}
+
+// Pops everything, checking the order against the expected priorities
+func drainHeap(h PriorityHeap[int, int], priorities map[int]int, t *testing.T) {
+	last := math.MinInt
+	for !h.Empty() {
+		value, priority, err := h.Pop()
+		if err != nil || priority < last || priorities[value] != priority {
+			t.Fatalf("popped %v with priority %v after %v, expected %v", value, priority, last, priorities[value])
+		}
+		delete(priorities, value)
+		last = priority
+	}
+	if len(priorities) != 0 {
+		t.Fatalf("%v values never popped", len(priorities))
+	}
+	if _, _, err := h.Pop(); err != ErrHeapEmpty {
+		t.Fatalf("pop from an empty heap: %v", err)
+	}
+}
+
+func TestPriorityHeaps(t *testing.T) {
+	for _, kind := range heapKinds {
+		t.Run(kind.name, func(t *testing.T) {
+			random := rand.New(rand.NewSource(4))
+			h := kind.newHeap()
+			handles := []*PriorityHandle[int, int]{}
+			priorities := map[int]int{}
+			for i := 0; i < 5000; i++ {
+				switch op := random.Intn(10); {
+				case op < 4:
+					priority := random.Intn(1000)
+					handles = append(handles, h.Push(len(handles), priority))
+					priorities[len(handles)-1] = priority
+				case op < 7 && len(handles) > 0:
+					value := random.Intn(len(handles))
+					_, queued := priorities[value]
+					priority := handles[value].Priority() - random.Intn(100) + 10
+					err := h.DecreaseKey(handles[value], priority)
+					switch {
+					case !queued && err != ErrUnknownHandle:
+						t.Fatalf("decreased popped value %v: %v", value, err)
+					case queued && priority > priorities[value] && err != ErrPriorityIncreased:
+						t.Fatalf("raised %v from %v to %v: %v", value, priorities[value], priority, err)
+					case queued && priority <= priorities[value]:
+						if err != nil {
+							t.Fatal(err)
+						}
+						priorities[value] = priority
+					}
+				case op < 8 && len(handles) > 0:
+					value := random.Intn(len(handles))
+					_, queued := priorities[value]
+					if err := h.Remove(handles[value]); queued != (err == nil) {
+						t.Fatalf("removal of %v: %v", value, err)
+					}
+					delete(priorities, value)
+				case !h.Empty():
+					first, priority, _ := h.Peek()
+					value, popped, err := h.Pop()
+					if err != nil || value != first || popped != priority || priorities[value] != priority {
+						t.Fatalf("popped %v with priority %v, peeked %v with %v", value, popped, first, priority)
+					}
+					for _, other := range priorities {
+						if other < priority {
+							t.Fatalf("popped priority %v before %v", priority, other)
+						}
+					}
+					delete(priorities, value)
+				}
+				if h.Count() != len(priorities) {
+					t.Fatalf("count %v, expected %v", h.Count(), len(priorities))
+				}
+			}
+			for value, handle := range handles {
+				if _, queued := priorities[value]; h.Contains(handle) != queued {
+					t.Fatalf("Contains(%v) is %v", value, !queued)
+				}
+			}
+			drainHeap(h, priorities, t)
+
+			if other := kind.newHeap(); other.Contains(h.Push(0, 0)) || other.Remove(handles[0]) == nil {
+				t.Fatalf("handle accepted by another heap")
+			}
+		})
+	}
+}
+
+func TestHeapMeld(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	pairing := []*PairingHeap[int, int]{}
+	fibonacci := []*FibonacciHeap[int, int]{}
+	for i := 0; i < 4; i++ {
+		pairing = append(pairing, NewPairingHeap[int](cmp.Compare[int]))
+		fibonacci = append(fibonacci, NewFibonacciHeap[int](cmp.Compare[int]))
+	}
+	pairingHandles := map[int]*PriorityHandle[int, int]{}
+	fibonacciHandles := map[int]*PriorityHandle[int, int]{}
+	priorities := map[int]int{}
+	for value := 0; value < 400; value++ {
+		priority := random.Intn(1000)
+		pairingHandles[value] = pairing[value%4].Push(value, priority)
+		fibonacciHandles[value] = fibonacci[value%4].Push(value, priority)
+		priorities[value] = priority
+	}
+	// pop a little first so that the Fibonacci heaps hold linked trees
+	for i := 1; i < 4; i++ {
+		value, priority, _ := fibonacci[i].Pop()
+		pairing[i].Remove(pairingHandles[value])
+		if priorities[value] != priority {
+			t.Fatalf("popped %v with priority %v", value, priority)
+		}
+		delete(priorities, value)
+	}
+
+	// meld pairwise, then into the first heap
+	pairing[2].Meld(pairing[3])
+	pairing[0].Meld(pairing[1])
+	pairing[0].Meld(pairing[2])
+	pairing[0].Meld(pairing[0])
+	fibonacci[2].Meld(fibonacci[3])
+	fibonacci[0].Meld(fibonacci[1])
+	fibonacci[0].Meld(fibonacci[2])
+	fibonacci[0].Meld(fibonacci[0])
+	for i := 1; i < 4; i++ {
+		if !pairing[i].Empty() || !fibonacci[i].Empty() {
+			t.Fatalf("melded heap %v not empty", i)
+		}
+	}
+	if pairing[0].Count() != len(priorities) || fibonacci[0].Count() != len(priorities) {
+		t.Fatalf("counts %v and %v after meld, expected %v", pairing[0].Count(), fibonacci[0].Count(), len(priorities))
+	}
+
+	// handles of the melded heaps now belong to the first one
+	for value, priority := range priorities {
+		if pairing[3].Contains(pairingHandles[value]) || fibonacci[3].Contains(fibonacciHandles[value]) {
+			t.Fatalf("emptied heap still contains %v", value)
+		}
+		priority -= random.Intn(500)
+		if err := pairing[0].DecreaseKey(pairingHandles[value], priority); err != nil {
+			t.Fatal(err)
+		}
+		if err := fibonacci[0].DecreaseKey(fibonacciHandles[value], priority); err != nil {
+			t.Fatal(err)
+		}
+		priorities[value] = priority
+	}
+	copied := map[int]int{}
+	for value, priority := range priorities {
+		copied[value] = priority
+	}
+	drainHeap(pairing[0], priorities, t)
+	drainHeap(fibonacci[0], copied, t)
+}
+
+// Checks that every element comes before its descendants on min levels
+// and after them on max levels
+func checkMinMaxHeap(h *MinMaxHeap[int, int], t *testing.T) {
+	for i, handle := range h.handles {
+		if handle.index != i {
+			t.Fatalf("handle at %v has index %v", i, handle.index)
+		}
+		for ancestor := (i - 1) / 2; i > 0; ancestor = (ancestor - 1) / 2 {
+			a := h.handles[ancestor].priority
+			if isMaxLevel(ancestor) && a < handle.priority || !isMaxLevel(ancestor) && a > handle.priority {
+				t.Fatalf("priority %v at %v below %v at %v", handle.priority, i, a, ancestor)
+			}
+			if ancestor == 0 {
+				break
+			}
+		}
+	}
+}
+
+func TestMinMaxHeap(t *testing.T) {
+	random := rand.New(rand.NewSource(6))
+	h := NewMinMaxHeap[int](cmp.Compare[int])
+	if _, _, err := h.PeekMax(); err != ErrHeapEmpty {
+		t.Fatalf("peeked into an empty heap: %v", err)
+	}
+	handles := []*PriorityHandle[int, int]{}
+	priorities := map[int]int{}
+	for i := 0; i < 3000; i++ {
+		switch random.Intn(5) {
+		case 0, 1:
+			priority := random.Intn(1000)
+			handles = append(handles, h.Push(len(handles), priority))
+			priorities[len(handles)-1] = priority
+		case 2:
+			value := random.Intn(len(handles) + 1)
+			if value < len(handles) && h.Contains(handles[value]) {
+				priority := random.Intn(1000)
+				if err := h.Update(handles[value], priority); err != nil {
+					t.Fatal(err)
+				}
+				priorities[value] = priority
+			}
+		case 3:
+			value := random.Intn(len(handles) + 1)
+			if value < len(handles) && h.Contains(handles[value]) {
+				h.Remove(handles[value])
+				delete(priorities, value)
+			}
+		case 4:
+			if h.Empty() {
+				continue
+			}
+			value, priority, err := h.PopMax()
+			if err != nil || priorities[value] != priority {
+				t.Fatalf("popped %v with priority %v, expected %v", value, priority, priorities[value])
+			}
+			delete(priorities, value)
+			for _, other := range priorities {
+				if other > priority {
+					t.Fatalf("popped maximum %v below %v", priority, other)
+				}
+			}
+		}
+		checkMinMaxHeap(h, t)
+	}
+
+	// alternate ends until the heap is empty
+	low, high := -1, 1000
+	for i := 0; !h.Empty(); i++ {
+		pop := h.Pop
+		if i%2 == 1 {
+			pop = h.PopMax
+		}
+		value, priority, _ := pop()
+		if priority < low || priority > high || priorities[value] != priority {
+			t.Fatalf("popped %v with priority %v outside [%v, %v]", value, priority, low, high)
+		}
+		if i%2 == 1 {
+			high = priority
+		} else {
+			low = priority
+		}
+		delete(priorities, value)
+	}
+	if len(priorities) != 0 {
+		t.Fatalf("%v values never popped", len(priorities))
+	}
+}
+
+func BenchmarkPriorityHeaps(b *testing.B) {
+	for _, kind := range heapKinds {
+		b.Run(kind.name, func(b *testing.B) {
+			random := rand.New(rand.NewSource(0))
+			h := kind.newHeap()
+			handles := make([]*PriorityHandle[int, int], 1000)
+			for i := range handles {
+				handles[i] = h.Push(i, random.Intn(1<<30))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				handle := handles[random.Intn(len(handles))]
+				h.DecreaseKey(handle, handle.Priority()-random.Intn(1<<10))
+				value, _, _ := h.Pop()
+				handles[value] = h.Push(value, random.Intn(1<<30))
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package collections
+
+import (
+	"errors"
+)
+
+// Binary min-heap: the item compare orders first is popped first.
+// compare returns a negative number if a comes before b, as for slices.SortFunc.
+// The zero value has no ordering and cannot be used, see NewPriorityQueue.
+type PriorityQueue[T any] struct {
+	items   []T
+	compare func(a, b T) int
+}
+
+var _ Queuer[int] = (*PriorityQueue[int])(nil)
+
+func NewPriorityQueue[T any](compare func(a, b T) int) *PriorityQueue[T] {
+	return &PriorityQueue[T]{compare: compare}
+}
+
+func (q *PriorityQueue[T]) Push(item T) {
+	q.items = append(q.items, item)
+	q.up(len(q.items) - 1)
+}
+
+func (q *PriorityQueue[T]) Pop() (T, error) {
+	if len(q.items) == 0 {
+		var item T
+		return item, errors.New("Queue is empty")
+	}
+	return q.Remove(0)
+}
+
+func (q *PriorityQueue[T]) Peek() (T, error) {
+	var item T
+	if len(q.items) == 0 {
+		return item, errors.New("Queue is empty")
+	}
+	return q.items[0], nil
+}
+
+// Item at the given heap position, position 0 is the front of the queue
+func (q *PriorityQueue[T]) Get(index int) (T, error) {
+	var item T
+	if index < 0 || index >= len(q.items) {
+		return item, errors.New("Index is out of range")
+	}
+	return q.items[index], nil
+}
+
+// Restores the heap order after the item at the given position changed, O(log(n))
+func (q *PriorityQueue[T]) Fix(index int) error {
+	if index < 0 || index >= len(q.items) {
+		return errors.New("Index is out of range")
+	}
+	if !q.down(index) {
+		q.up(index)
+	}
+	return nil
+}
+
+// Removes the item at the given position, O(log(n))
+func (q *PriorityQueue[T]) Remove(index int) (T, error) {
+	var item T
+	if index < 0 || index >= len(q.items) {
+		return item, errors.New("Index is out of range")
+	}
+	item = q.items[index]
+	last := len(q.items) - 1
+	q.swap(index, last)
+	var zero T
+	q.items[last] = zero
+	q.items = q.items[:last]
+	if index < last && !q.down(index) {
+		q.up(index)
+	}
+	return item, nil
+}
+
+func (q *PriorityQueue[T]) Count() int {
+	return len(q.items)
+}
+
+func (q *PriorityQueue[T]) Empty() bool {
+	return len(q.items) == 0
+}
+
+func (q *PriorityQueue[T]) less(i, j int) bool {
+	return q.compare(q.items[i], q.items[j]) < 0
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+}
+
+func (q *PriorityQueue[T]) up(i int) {
+	siftUp(i, 2, q.less, q.swap)
+}
+
+func (q *PriorityQueue[T]) down(i int) bool {
+	return siftDown(i, len(q.items), 2, q.less, q.swap)
+}
+
+// Binary min-heap of values with separate priorities. Push returns a handle
+// through which the priority of a queued value can be changed or the value
+// removed in O(log(n)).
+type IndexedPriorityQueue[T, P any] struct {
+	DaryHeap[T, P]
+}
+
+func NewIndexedPriorityQueue[T, P any](compare func(a, b P) int) *IndexedPriorityQueue[T, P] {
+	return &IndexedPriorityQueue[T, P]{*NewDaryHeap[T](2, compare)}
+}
+
+// Moves the element at i of a heap with the given arity towards the root
+// while it is less than its parent
+func siftUp(i, arity int, less func(i, j int) bool, swap func(i, j int)) {
+	for i > 0 {
+		parent := (i - 1) / arity
+		if !less(i, parent) {
+			return
+		}
+		swap(i, parent)
+		i = parent
+	}
+}
+
+// Moves the element at i of a heap with n elements and the given arity towards
+// the leaves while a child is less than it. Reports whether the element moved.
+func siftDown(i, n, arity int, less func(i, j int) bool, swap func(i, j int)) bool {
+	start := i
+	for {
+		first := arity*i + 1
+		if first >= n {
+			break
+		}
+		child := first
+		for sibling := first + 1; sibling < min(first+arity, n); sibling++ {
+			if less(sibling, child) {
+				child = sibling
+			}
+		}
+		if !less(child, i) {
+			break
+		}
+		swap(i, child)
+		i = child
+	}
+	return i > start
+}
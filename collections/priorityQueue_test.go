@@ -0,0 +1,166 @@
+package collections
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type task struct {
+	name     int
+	priority int
+}
+
+func compareTasks(a, b *task) int {
+	return cmp.Or(cmp.Compare(a.priority, b.priority), cmp.Compare(a.name, b.name))
+}
+
+// Pops everything, checking that the items come out in order
+func drainInOrder[T any](q *PriorityQueue[T], compare func(a, b T) int, t *testing.T) []T {
+	items := []T{}
+	for !q.Empty() {
+		item, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) > 0 && compare(items[len(items)-1], item) > 0 {
+			t.Fatalf("popped %v after %v", item, items[len(items)-1])
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestPriorityQueue(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	q := NewPriorityQueue(cmp.Compare[int])
+	if _, err := q.Pop(); err == nil {
+		t.Fatalf("popped from an empty queue")
+	}
+	expected := []int{}
+	for i := 0; i < 1000; i++ {
+		value := random.Intn(100)
+		q.Push(value)
+		expected = append(expected, value)
+		if top, _ := q.Peek(); top != slices.Min(expected) {
+			t.Fatalf("peeked %v, expected %v", top, slices.Min(expected))
+		}
+	}
+	// remove some items from the middle of the heap
+	for i := 0; i < 100; i++ {
+		removed, err := q.Remove(random.Intn(q.Count()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected = slices.Delete(expected, slices.Index(expected, removed), slices.Index(expected, removed)+1)
+	}
+	if _, err := q.Remove(q.Count()); err == nil {
+		t.Fatalf("removed past the end")
+	}
+	slices.Sort(expected)
+	if items := drainInOrder(q, cmp.Compare[int], t); !slices.Equal(items, expected) {
+		t.Fatalf("popped %v, expected %v", items, expected)
+	}
+}
+
+func TestPriorityQueueFix(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	q := NewPriorityQueue(compareTasks)
+	for i := 0; i < 200; i++ {
+		q.Push(&task{i, random.Intn(1000)})
+	}
+	for i := 0; i < 500; i++ {
+		index := random.Intn(q.Count())
+		item, _ := q.Get(index)
+		item.priority = random.Intn(1000)
+		if err := q.Fix(index); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if items := drainInOrder(q, compareTasks, t); len(items) != 200 {
+		t.Fatalf("popped %v items, expected 200", len(items))
+	}
+}
+
+func TestIndexedPriorityQueue(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	q := NewIndexedPriorityQueue[int](cmp.Compare[int])
+	handles := map[int]*PriorityHandle[int, int]{}
+	priorities := map[int]int{}
+	for i := 0; i < 500; i++ {
+		priority := random.Intn(10000)
+		handles[i] = q.Push(i, priority)
+		priorities[i] = priority
+	}
+
+	for i := 0; i < 2000; i++ {
+		value := random.Intn(500)
+		handle := handles[value]
+		switch random.Intn(3) {
+		case 0:
+			priority := random.Intn(10000)
+			err := q.DecreaseKey(handle, priority)
+			if _, queued := priorities[value]; queued && priority <= priorities[value] {
+				if err != nil {
+					t.Fatal(err)
+				}
+				priorities[value] = priority
+			} else if err == nil {
+				t.Fatalf("priority of %v raised from %v to %v by DecreaseKey", value, priorities[value], priority)
+			}
+		case 1:
+			priority := random.Intn(10000)
+			err := q.Update(handle, priority)
+			if _, queued := priorities[value]; queued != (err == nil) {
+				t.Fatalf("update of %v: %v", value, err)
+			}
+			if err == nil {
+				priorities[value] = priority
+			}
+		case 2:
+			err := q.Remove(handle)
+			if _, queued := priorities[value]; queued != (err == nil) {
+				t.Fatalf("removal of %v: %v", value, err)
+			}
+			delete(priorities, value)
+		}
+		if q.Count() != len(priorities) {
+			t.Fatalf("count %v, expected %v", q.Count(), len(priorities))
+		}
+	}
+
+	last := -1
+	for !q.Empty() {
+		value, priority, err := q.Pop()
+		if err != nil || priority < last || priorities[value] != priority {
+			t.Fatalf("popped %v with priority %v after %v, expected %v", value, priority, last, priorities[value])
+		}
+		if q.Contains(handles[value]) {
+			t.Fatalf("popped handle %v still contained", value)
+		}
+		delete(priorities, value)
+		last = priority
+	}
+	if len(priorities) != 0 {
+		t.Fatalf("%v values never popped", len(priorities))
+	}
+
+	other := NewIndexedPriorityQueue[int](cmp.Compare[int])
+	if err := other.Remove(q.Push(1, 1)); err == nil {
+		t.Fatalf("removed a handle of another queue")
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	random := rand.New(rand.NewSource(0))
+	q := NewPriorityQueue(cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		q.Push(random.Int())
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(random.Int())
+		q.Pop()
+	}
+}
@@ -10,35 +10,96 @@ type Queuer[T any] interface {
 	Count() int
 }
 
-type Queue[T comparable] []T
+// Smallest non-empty backing array, the queue never shrinks below it
+const minQueueCapacity = 8
+
+// Circular buffer which doubles when full and halves when a quarter full,
+// so its memory follows the number of queued items.
+// The zero value is an empty queue.
+type Queue[T comparable] struct {
+	items []T
+	head  int
+	count int
+}
 
 func (q *Queue[T]) Push(item T) {
-	*q = append(*q, item)
+	if q.count == len(q.items) {
+		q.resize(max(minQueueCapacity, 2*len(q.items)))
+	}
+	q.items[(q.head+q.count)%len(q.items)] = item
+	q.count++
 }
 
 func (q *Queue[T]) Pop() (T, error) {
 	var item T
-	if len(*q) == 0 {
+	if q.count == 0 {
 		return item, errors.New("Queue is empty")
 	}
-	item = (*q)[0]
-	*q = (*q)[1:]
+	item = q.items[q.head]
+	// drop the reference so that the popped item can be collected
+	var zero T
+	q.items[q.head] = zero
+	q.head = (q.head + 1) % len(q.items)
+	q.count--
+	if len(q.items) > minQueueCapacity && q.count <= len(q.items)/4 {
+		q.resize(len(q.items) / 2)
+	}
 	return item, nil
 }
 
 func (q *Queue[T]) Peek() (T, error) {
 	var item T
-	if len(*q) == 0 {
+	if q.count == 0 {
 		return item, errors.New("Queue is empty")
 	}
-	item = (*q)[0]
+	item = q.items[q.head]
+	return item, nil
+}
+
+// Item at the given position from the front of the queue
+func (q *Queue[T]) Get(index int) (T, error) {
+	var item T
+	if index < 0 || index >= q.count {
+		return item, errors.New("Index is out of range")
+	}
+	item = q.items[(q.head+index)%len(q.items)]
 	return item, nil
 }
 
+// Replaces the item at the given position from the front of the queue
+func (q *Queue[T]) Set(index int, item T) error {
+	if index < 0 || index >= q.count {
+		return errors.New("Index is out of range")
+	}
+	q.items[(q.head+index)%len(q.items)] = item
+	return nil
+}
+
 func (q *Queue[T]) Count() int {
-	return len(*q)
+	return q.count
 }
 
 func (q *Queue[T]) Empty() bool {
-	return len(*q) == 0
+	return q.count == 0
+}
+
+// Number of items the queue holds before it has to grow
+func (q *Queue[T]) Cap() int {
+	return len(q.items)
+}
+
+// Removes all items and releases the backing array
+func (q *Queue[T]) Clear() {
+	*q = Queue[T]{}
+}
+
+// Moves the items to the front of a new backing array of the given capacity
+func (q *Queue[T]) resize(capacity int) {
+	items := make([]T, capacity)
+	if q.count > 0 {
+		n := copy(items, q.items[q.head:min(q.head+q.count, len(q.items))])
+		copy(items[n:], q.items[:q.count-n])
+	}
+	q.items = items
+	q.head = 0
 }
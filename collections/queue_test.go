@@ -0,0 +1,143 @@
+package collections
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestQueue(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	queue := &Queue[int]{}
+	model := []int{}
+	for i := 0; i < 100000; i++ {
+		// pushes outnumber pops for the first half, then the queue drains
+		if random.Intn(10) < 6 == (i < 50000) {
+			queue.Push(i)
+			model = append(model, i)
+		} else {
+			item, err := queue.Pop()
+			if len(model) == 0 {
+				if err == nil {
+					t.Fatalf("popped %v from an empty queue", item)
+				}
+				continue
+			}
+			if err != nil || item != model[0] {
+				t.Fatalf("popped %v, %v, expected %v", item, err, model[0])
+			}
+			model = model[1:]
+		}
+
+		if queue.Count() != len(model) || queue.Empty() != (len(model) == 0) {
+			t.Fatalf("count %v, expected %v", queue.Count(), len(model))
+		}
+		if queue.Cap() > max(minQueueCapacity, 4*len(model)) {
+			t.Fatalf("capacity %v for %v items", queue.Cap(), len(model))
+		}
+		if len(model) > 0 {
+			index := random.Intn(len(model))
+			if item, _ := queue.Get(index); item != model[index] {
+				t.Fatalf("item %v is %v, expected %v", index, item, model[index])
+			}
+			if item, _ := queue.Peek(); item != model[0] {
+				t.Fatalf("peeked %v, expected %v", item, model[0])
+			}
+		}
+	}
+}
+
+func TestQueueIndexing(t *testing.T) {
+	queue := &Queue[string]{}
+	for _, item := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		queue.Push(item)
+	}
+	// wrap around the end of the backing array
+	queue.Pop()
+	queue.Pop()
+	queue.Push("i")
+	if err := queue.Set(6, "x"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"c", "d", "e", "f", "g", "h", "x"}
+	for i, item := range expected {
+		if got, _ := queue.Get(i); got != item {
+			t.Fatalf("item %v is %v, expected %v", i, got, item)
+		}
+	}
+	if _, err := queue.Get(len(expected)); err == nil {
+		t.Fatalf("index past the end accepted")
+	}
+	if err := queue.Set(-1, "y"); err == nil {
+		t.Fatalf("negative index accepted")
+	}
+
+	queue.Clear()
+	if !queue.Empty() || queue.Cap() != 0 {
+		t.Fatalf("cleared queue holds %v items, capacity %v", queue.Count(), queue.Cap())
+	}
+	if _, err := queue.Pop(); err == nil {
+		t.Fatalf("popped from a cleared queue")
+	}
+	queue.Push("z")
+	if item, _ := queue.Pop(); item != "z" {
+		t.Fatalf("popped %v after clearing", item)
+	}
+}
+
+// Steady push/pop traffic must not grow the backing array, and a drained
+// burst must not leave it large
+func TestQueueChurnCapacity(t *testing.T) {
+	queue := &Queue[int]{}
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	warm := queue.Cap()
+	for i := 0; i < 100000; i++ {
+		queue.Push(i)
+		queue.Pop()
+		if queue.Cap() != warm {
+			t.Fatalf("capacity %v after %v pushes and pops, %v before", queue.Cap(), i+1, warm)
+		}
+	}
+	for i := 0; i < 100000; i++ {
+		queue.Push(i)
+	}
+	for queue.Count() > 1000 {
+		queue.Pop()
+	}
+	for i := 0; i < 10000; i++ {
+		queue.Push(i)
+		queue.Pop()
+	}
+	if queue.Cap() > 4*queue.Count() {
+		t.Fatalf("capacity %v for %v items", queue.Cap(), queue.Count())
+	}
+}
+
+// Constant queue length, reports zero allocations per operation once warmed up
+func BenchmarkQueueChurn(b *testing.B) {
+	queue := &Queue[int]{}
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queue.Push(i)
+		queue.Pop()
+	}
+}
+
+// Fills and drains the queue, growing and shrinking the backing array
+func BenchmarkQueueBurst(b *testing.B) {
+	queue := &Queue[int]{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1024; j++ {
+			queue.Push(j)
+		}
+		for !queue.Empty() {
+			queue.Pop()
+		}
+	}
+}
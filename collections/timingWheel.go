@@ -0,0 +1,203 @@
+package collections
+
+import (
+	"sync"
+	"time"
+)
+
+// Hierarchical timing wheel: level 0 has a bucket per tick, and every further
+// level has buckets as wide as a whole turn of the level below. Timers are
+// hashed into the lowest level whose turn reaches their deadline and move down
+// a level whenever the wheel reaches their bucket, so scheduling and
+// cancelling take O(1) however many timers there are. Timers fire at the first
+// tick at or after their deadline. Safe for concurrent use.
+type TimingWheel[T any] struct {
+	mutex sync.Mutex
+	clock Clock
+	tick  time.Duration
+	slots int
+	// Time of tick 0
+	start time.Time
+	// Last tick that was processed
+	current uint64
+	// Buckets of every level, and the number of ticks a bucket of the level spans
+	levels [][]timerList[T]
+	spans  []uint64
+	// Timers whose tick has already been processed
+	overdue timerList[T]
+	count   int
+}
+
+// Timer scheduled in a TimingWheel
+type WheelTimer[T any] struct {
+	Value    T
+	deadline time.Time
+	// Tick at which the timer fires
+	expiry uint64
+	// Wheel and bucket holding the timer, nil once it fired or was cancelled
+	wheel      *TimingWheel[T]
+	list       *timerList[T]
+	prev, next *WheelTimer[T]
+}
+
+func (t *WheelTimer[T]) Deadline() time.Time {
+	return t.deadline
+}
+
+// Doubly linked timers in scheduling order
+type timerList[T any] struct {
+	first, last *WheelTimer[T]
+}
+
+// Wheel with the given tick length and number of buckets per level, reading
+// the time from clock, SystemClock if nil. Panics unless the tick is positive
+// and there are 2 to 65536 slots.
+func NewTimingWheel[T any](tick time.Duration, slots int, clock Clock) *TimingWheel[T] {
+	if tick <= 0 || slots < 2 || slots > 1<<16 {
+		panic("TimingWheel needs a positive tick and 2 to 65536 slots")
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &TimingWheel[T]{clock: clock, tick: tick, slots: slots, start: clock.Now()}
+}
+
+// Schedules the value for the given deadline, O(1)
+func (w *TimingWheel[T]) Schedule(value T, deadline time.Time) *WheelTimer[T] {
+	timer := &WheelTimer[T]{Value: value, deadline: deadline, wheel: w}
+	if elapsed := deadline.Sub(w.start); elapsed > 0 {
+		// round up to the next tick
+		timer.expiry = uint64(elapsed / w.tick)
+		if elapsed%w.tick != 0 {
+			timer.expiry++
+		}
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.place(timer)
+	w.count++
+	return timer
+}
+
+// Schedules the value once the delay has passed from now
+func (w *TimingWheel[T]) ScheduleAfter(value T, delay time.Duration) *WheelTimer[T] {
+	return w.Schedule(value, w.clock.Now().Add(delay))
+}
+
+// Removes a timer before it fires, O(1). Reports whether the timer was scheduled.
+func (w *TimingWheel[T]) Cancel(timer *WheelTimer[T]) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if timer == nil || timer.wheel != w {
+		return false
+	}
+	timer.list.remove(timer)
+	timer.wheel = nil
+	w.count--
+	return true
+}
+
+// Processes the ticks up to the clock's current time and returns the values of
+// the timers that fired, in order of their ticks. O(1) per tick and timer.
+func (w *TimingWheel[T]) Advance() []T {
+	target := uint64(0)
+	if elapsed := w.clock.Now().Sub(w.start); elapsed > 0 {
+		target = uint64(elapsed / w.tick)
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	fired := []T{}
+	collect := func(list *timerList[T]) {
+		for timer := list.first; timer != nil; timer = list.first {
+			list.remove(timer)
+			timer.wheel = nil
+			fired = append(fired, timer.Value)
+			w.count--
+		}
+	}
+	collect(&w.overdue)
+	if w.count == 0 {
+		w.current = max(w.current, target)
+	}
+	for w.current < target {
+		w.current++
+		// higher levels first, as their timers may land in a lower bucket due now
+		for level := len(w.levels) - 1; level > 0; level-- {
+			if w.current%w.spans[level] == 0 {
+				w.cascade(&w.levels[level][(w.current/w.spans[level])%uint64(w.slots)])
+			}
+		}
+		if len(w.levels) > 0 {
+			collect(&w.levels[0][w.current%uint64(w.slots)])
+		}
+		collect(&w.overdue)
+		if w.count == 0 {
+			w.current = target
+		}
+	}
+	return fired
+}
+
+// Number of scheduled timers
+func (w *TimingWheel[T]) Count() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.count
+}
+
+// Puts the timer in the lowest level whose turn reaches its tick.
+// Must hold the mutex.
+func (w *TimingWheel[T]) place(timer *WheelTimer[T]) {
+	if timer.expiry <= w.current {
+		w.overdue.append(timer)
+		return
+	}
+	for level := 0; ; level++ {
+		if level == len(w.levels) {
+			span := uint64(1)
+			if level > 0 {
+				span = w.spans[level-1] * uint64(w.slots)
+			}
+			w.levels = append(w.levels, make([]timerList[T], w.slots))
+			w.spans = append(w.spans, span)
+		}
+		span := w.spans[level]
+		if timer.expiry/span-w.current/span < uint64(w.slots) {
+			w.levels[level][(timer.expiry/span)%uint64(w.slots)].append(timer)
+			return
+		}
+	}
+}
+
+// Moves the timers of a bucket a level down. Must hold the mutex.
+func (w *TimingWheel[T]) cascade(list *timerList[T]) {
+	for timer := list.first; timer != nil; timer = list.first {
+		list.remove(timer)
+		w.place(timer)
+	}
+}
+
+func (l *timerList[T]) append(timer *WheelTimer[T]) {
+	timer.list, timer.prev, timer.next = l, l.last, nil
+	if l.last == nil {
+		l.first = timer
+	} else {
+		l.last.next = timer
+	}
+	l.last = timer
+}
+
+func (l *timerList[T]) remove(timer *WheelTimer[T]) {
+	if timer.prev == nil {
+		l.first = timer.next
+	} else {
+		timer.prev.next = timer.next
+	}
+	if timer.next == nil {
+		l.last = timer.prev
+	} else {
+		timer.next.prev = timer.prev
+	}
+	timer.list, timer.prev, timer.next = nil, nil, nil
+}
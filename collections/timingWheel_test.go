@@ -0,0 +1,118 @@
+package collections
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimingWheel(t *testing.T) {
+	clock := NewManualClock(epoch)
+	w := NewTimingWheel[int](time.Millisecond, 8, clock)
+	random := rand.New(rand.NewSource(10))
+
+	// deadlines spread over several levels, in milliseconds from the epoch
+	deadlines := map[int]int{}
+	timers := map[int]*WheelTimer[int]{}
+	for i := 0; i < 3000; i++ {
+		deadline := random.Intn(1 << (3 * (1 + random.Intn(4))))
+		if random.Intn(10) == 0 {
+			deadline = random.Intn(100000)
+		}
+		deadlines[i] = deadline
+		timers[i] = w.Schedule(i, epoch.Add(time.Duration(deadline)*time.Millisecond+time.Duration(1+random.Intn(999))))
+	}
+	for i := 0; i < 300; i++ {
+		value := random.Intn(len(timers))
+		if _, scheduled := deadlines[value]; w.Cancel(timers[value]) != scheduled {
+			t.Fatalf("cancel of %v", value)
+		}
+		delete(deadlines, value)
+	}
+	if w.Count() != len(deadlines) {
+		t.Fatalf("count %v, expected %v", w.Count(), len(deadlines))
+	}
+
+	now := 0
+	for len(deadlines) > 0 {
+		step := 1 + random.Intn(50)
+		if random.Intn(20) == 0 {
+			step = random.Intn(20000)
+		}
+		clock.Advance(time.Duration(step) * time.Millisecond)
+		now += step
+		fired := w.Advance()
+		for _, value := range fired {
+			deadline, scheduled := deadlines[value]
+			// the fraction of a millisecond rounds up to the next tick
+			if !scheduled || deadline+1 > now {
+				t.Fatalf("timer %v due at %v fired at %v", value, deadline, now)
+			}
+			if w.Cancel(timers[value]) {
+				t.Fatalf("cancelled timer %v after it fired", value)
+			}
+			delete(deadlines, value)
+		}
+		for value, deadline := range deadlines {
+			if deadline+1 <= now {
+				t.Fatalf("timer %v due at %v not fired at %v", value, deadline, now)
+			}
+		}
+		if w.Count() != len(deadlines) {
+			t.Fatalf("count %v, expected %v", w.Count(), len(deadlines))
+		}
+	}
+}
+
+func TestTimingWheelOrder(t *testing.T) {
+	clock := NewManualClock(epoch)
+	w := NewTimingWheel[int](time.Second, 4, clock)
+	for _, delay := range []int{70, 3, 1, 0, 17, -5, 2, 64} {
+		w.ScheduleAfter(delay, time.Duration(delay)*time.Second)
+	}
+	// past deadlines fire right away
+	if fired := w.Advance(); !slices.Equal(fired, []int{0, -5}) {
+		t.Fatalf("fired %v", fired)
+	}
+	clock.Advance(100 * time.Second)
+	if fired := w.Advance(); !slices.Equal(fired, []int{1, 2, 3, 17, 64, 70}) {
+		t.Fatalf("fired %v", fired)
+	}
+	// timers scheduled after a long idle time
+	clock.Advance(1000 * time.Hour)
+	w.Advance()
+	w.ScheduleAfter(1, 1500*time.Millisecond)
+	clock.Advance(time.Second)
+	if fired := w.Advance(); len(fired) != 0 {
+		t.Fatalf("fired %v early", fired)
+	}
+	clock.Advance(time.Second)
+	if fired := w.Advance(); !slices.Equal(fired, []int{1}) {
+		t.Fatalf("fired %v", fired)
+	}
+	other := NewTimingWheel[int](time.Second, 4, clock)
+	if other.Cancel(w.ScheduleAfter(2, time.Second)) {
+		t.Fatalf("cancelled a timer of another wheel")
+	}
+}
+
+func BenchmarkTimingWheel(b *testing.B) {
+	clock := NewManualClock(epoch)
+	w := NewTimingWheel[int](time.Millisecond, 256, clock)
+	random := rand.New(rand.NewSource(0))
+	timers := make([]*WheelTimer[int], 100000)
+	for i := range timers {
+		timers[i] = w.ScheduleAfter(i, time.Duration(random.Intn(60000))*time.Millisecond)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := i % len(timers)
+		w.Cancel(timers[k])
+		timers[k] = w.ScheduleAfter(k, time.Duration(random.Intn(60000))*time.Millisecond)
+		if i%100 == 0 {
+			clock.Advance(time.Millisecond)
+			w.Advance()
+		}
+	}
+}
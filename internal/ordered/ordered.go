@@ -0,0 +1,18 @@
+// Helpers shared by the collections and the algorithms over them
+package ordered
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Returns the keys of a map in ascending order,
+// giving graph traversals a deterministic order
+func Keys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,15 @@
+package ordered
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	if keys := Keys(map[int]bool{}); len(keys) != 0 {
+		t.Fatalf("keys %v of an empty map", keys)
+	}
+	if keys := Keys(map[int]string{5: "e", -1: "z", 3: "c"}); !slices.Equal(keys, []int{-1, 3, 5}) {
+		t.Fatalf("keys %v", keys)
+	}
+}